@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -21,68 +24,333 @@ This command will:
 - Scan for translation files strictly from messages/*.json
 - Scan source files (.jsx and .tsx only) for translation usage
 - Report unused translations (declared but not used)
-- Report undeclared translations (used but not declared)`,
+- Report undeclared translations (used but not declared)
+
+The project path is a directory, or a zip or tar(.gz) archive of one.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		projectPath := args[0]
+		// The arguments are valid from here on; errors are not about usage
+		cmd.SilenceUsage = true
 		
-		// Show progress indicator
+		format, _ := cmd.Flags().GetString("format")
+		if err := validateFormat(format); err != nil {
+			return err
+		}
+		if format == formatAuto {
+			format = detectFormat(os.Getenv)
+		}
+		watch, _ := cmd.Flags().GetBool("watch")
+		if watch && format != formatConsole {
+			return fmt.Errorf("--watch only supports the %s format", formatConsole)
+		}
 		quiet, _ := cmd.Flags().GetBool("quiet")
-		if !quiet {
-			fmt.Println("🔍 Analyzing project...")
-			fmt.Println("  ↳ Scanning files...")
+		outputs, err := outputSpecs(cmd, format, projectPath, quiet)
+		if err != nil {
+			return err
+		}
+		
+		// Progress and status messages go to stderr, so that stdout only
+		// carries results. A structured report on stdout is usually piped
+		// into another tool, which silences them like --quiet does.
+		progress := io.Writer(os.Stderr)
+		for _, output := range outputs {
+			if output.toStdout() && output.format != formatConsole {
+				progress = io.Discard
+			}
+		}
+		if quiet {
+			progress = io.Discard
+		}
+		if watch && progress == io.Discard && !quiet {
+			return fmt.Errorf("--watch cannot be used with an output to stdout")
+		}
+		fmt.Fprintln(progress, "🔍 Analyzing project...")
+		fmt.Fprintln(progress, "  ↳ Scanning files...")
+		
+		// Archives and the git index are read into memory and analyzed from
+		// there
+		var projectFS fs.FS
+		noCache, _ := cmd.Flags().GetBool("no-cache")
+		staged, _ := cmd.Flags().GetBool("staged")
+		diffBase, _ := cmd.Flags().GetString("diff-base")
+		var stagedChanges *analyzer.StagedChanges
+		if diffBase != "" {
+			switch {
+			case watch:
+				return fmt.Errorf("--watch cannot be used with --diff-base")
+			case staged:
+				return fmt.Errorf("--staged cannot be used with --diff-base")
+			case baselineFlagsSet(cmd):
+				return fmt.Errorf("baselines cannot be used with --diff-base, which compares with the base revision instead")
+			}
+			head, err := analyzer.GitRevisionFS(cmd.Context(), projectPath, diffHead)
+			if err != nil {
+				return fmt.Errorf("could not read revision %s: %w", diffHead, err)
+			}
+			projectFS = head
+		} else if staged {
+			if watch {
+				return fmt.Errorf("--watch cannot be used with --staged")
+			}
+			if cmd.Flags().Changed("baseline-write") {
+				return fmt.Errorf("--baseline-write cannot be used with --staged, which only reports part of the issues")
+			}
+			changes, err := analyzer.LoadStagedChanges(cmd.Context(), projectPath)
+			if err != nil {
+				return fmt.Errorf("could not read staged changes: %w", err)
+			}
+			stagedChanges, projectFS = changes, changes.FS
+		} else if info, err := os.Stat(projectPath); err == nil && !info.IsDir() {
+			if watch {
+				return fmt.Errorf("--watch cannot be used with an archive")
+			}
+			if projectFS, err = analyzer.OpenArchive(projectPath); err != nil {
+				return err
+			}
+			// Keep the parse cache out of the archive path
+			noCache = true
+		}
+		
+		// The flags apply on top of the configuration file, which is read
+		// again when it changes in watch mode
+		buildConfig := func() (*analyzer.Config, error) {
+			config, err := loadConfig(cmd, projectPath, projectFS)
+			if err != nil {
+				return nil, err
+			}
+			if cmd.Flags().Changed("min-confidence") {
+				minConfidence, _ := cmd.Flags().GetFloat64("min-confidence")
+				if minConfidence < 0 || minConfidence > 1 {
+					return nil, fmt.Errorf("invalid --min-confidence %v: must be between 0 and 1", minConfidence)
+				}
+				config.MinConfidence = &minConfidence
+			}
+			include, _ := cmd.Flags().GetStringArray("include")
+			exclude, _ := cmd.Flags().GetStringArray("exclude")
+			config.Include = append(config.Include, include...)
+			config.Exclude = append(config.Exclude, exclude...)
+			// Files read from git are tracked, which ignore files do not apply to
+			if noGitignore, _ := cmd.Flags().GetBool("no-gitignore"); noGitignore || staged || diffBase != "" {
+				gitignore := false
+				config.Gitignore = &gitignore
+			}
+			if followSymlinks, _ := cmd.Flags().GetBool("follow-symlinks"); followSymlinks {
+				config.FollowSymlinks = true
+			}
+			return config, nil
+		}
+		config, err := buildConfig()
+		if err != nil {
+			return err
+		}
+		
+		failOn := config.FailThreshold()
+		if cmd.Flags().Changed("fail-on") {
+			failOnFlag, _ := cmd.Flags().GetString("fail-on")
+			if failOn, err = analyzer.ParseSeverity(failOnFlag); err != nil {
+				return fmt.Errorf("invalid --fail-on: %w", err)
+			}
+		}
+		
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		newCache := func(config *analyzer.Config) *analyzer.ParseCache {
+			if !noCache {
+				return analyzer.NewProjectCache(projectPath, config)
+			} else if watch {
+				// Keep parse results in memory so that re-runs only parse changed files
+				return analyzer.NewParseCache("", analyzer.NewTranslationParser(config.ParserOptions()...).ConfigHash())
+			}
+			return nil
 		}
 		
-		analyzer := analyzer.NewAnalyzer(projectPath)
+		options := []analyzer.Option{
+			analyzer.WithConfig(config),
+			analyzer.WithConcurrency(jobs),
+			analyzer.WithCache(newCache(config)),
+		}
+		projectAnalyzer, err := analyzer.New(append(options, analyzer.WithDir(projectPath), analyzer.WithFS(projectFS))...)
+		if err != nil {
+			return err
+		}
 		
 		// Add progress callback with spinner
 		spinChars := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 		spinIdx := 0
 		lastStage := ""
 		
-		analyzer.SetProgressCallback(func(stage string, progress int, total int) {
-			if !quiet {
-				if stage != lastStage {
-					if lastStage != "" {
-						fmt.Println()
-					}
-					lastStage = stage
-				}
-				
-				spinChar := spinChars[spinIdx%len(spinChars)]
-				spinIdx++
-				
-				if total > 0 {
-					percent := int((float64(progress) / float64(total)) * 100)
-					fmt.Printf("\r  %s %s... %d/%d (%d%%)   ", spinChar, stage, progress, total, percent)
-				} else {
-					fmt.Printf("\r  %s %s...   ", spinChar, stage)
+		projectAnalyzer.SetProgressCallback(func(stage string, done int, total int) {
+			if stage != lastStage {
+				if lastStage != "" {
+					fmt.Fprintln(progress)
 				}
+				lastStage = stage
+			}
+			
+			spinChar := spinChars[spinIdx%len(spinChars)]
+			spinIdx++
+			
+			if total > 0 {
+				percent := int((float64(done) / float64(total)) * 100)
+				fmt.Fprintf(progress, "\r  %s %s... %d/%d (%d%%)   ", spinChar, stage, done, total, percent)
+			} else {
+				fmt.Fprintf(progress, "\r  %s %s...   ", spinChar, stage)
 			}
 		})
 		
-		results, err := analyzer.Analyze()
+		var baseline *analyzer.Baseline
+		baselinePath, _ := cmd.Flags().GetString("baseline")
+		if baselinePath != "" {
+			if baseline, err = analyzer.LoadBaseline(baselinePath); err != nil {
+				return err
+			}
+		}
+		
+		// The baseline to write records every current issue, including the
+		// ones the --baseline file already knows
+		baselineWritePath, _ := cmd.Flags().GetString("baseline-write")
+		var current *analyzer.Baseline
+		
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		analyze := func(ctx context.Context) (*analyzer.AnalysisResult, error) {
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			results, err := projectAnalyzer.Analyze(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if baselineWritePath != "" && current == nil {
+				current = analyzer.NewBaseline(results, projectPath)
+			}
+			if baseline != nil {
+				results.Baseline = baseline.Apply(results, projectPath)
+			}
+			// After the baseline, so that issues outside the staged changes
+			// do not count as fixed
+			if stagedChanges != nil {
+				stagedChanges.Apply(results, projectPath)
+			}
+			if diffBase != "" {
+				baseResults, baseMessages, err := analyzeRevision(ctx, projectPath, diffBase, options...)
+				if err != nil {
+					return nil, err
+				}
+				// Diagnostics are shown once the progress line is done
+				for _, diagnostic := range baseResults.Diagnostics {
+					diagnostic.Message = fmt.Sprintf("%s: %s", diffBase, diagnostic.Message)
+					results.Diagnostics = append(results.Diagnostics, diagnostic)
+				}
+				headMessages, err := projectAnalyzer.Messages()
+				if err != nil {
+					return nil, err
+				}
+				results.Diff = analyzer.DiffResults(baseResults, results, baseMessages, headMessages)
+				results.Diff.Base, results.Diff.Head = diffBase, diffHead
+			}
+			return results, nil
+		}
+		
+		results, err := analyze(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("analysis failed: %w", err)
 		}
 		
-		if !quiet {
-			fmt.Println("\r  ↳ Analysis complete!                      ")
-			fmt.Println()
+		fmt.Fprintln(progress, "\r  ↳ Analysis complete!                      ")
+		displayDiagnostics(results.Diagnostics)
+		fmt.Fprintln(progress)
+		if stagedChanges != nil {
+			fmt.Fprintf(progress, "📝 Reporting issues of %d staged file(s) only\n\n", len(stagedChanges.Files))
 		}
 		
-		// Generate markdown report if flag is set (do this before display to avoid os.Exit)
-		generateReport, _ := cmd.Flags().GetBool("report")
-		if generateReport {
-			reportFile, _ := cmd.Flags().GetString("report-file")
-			if err := generateMarkdownReport(results, projectPath, reportFile, cmd); err != nil {
-				return fmt.Errorf("failed to generate report: %w", err)
+		// Record the current issues as the new baseline
+		if baselineWritePath != "" {
+			if err := current.Save(baselineWritePath); err != nil {
+				return err
 			}
+			fmt.Fprintf(progress, "📌 Baseline with %d issue(s) written to %s\n\n", countEntries(current.Entries), baselineWritePath)
+			// Everything that was just recorded is known from now on. Issues
+			// of the --baseline file are gone from results already, so its
+			// report still tells what was fixed.
+			report := current.Apply(results, projectPath)
+			if results.Baseline != nil {
+				report.Suppressed += results.Baseline.Suppressed
+				report.Fixed = results.Baseline.Fixed
+			}
+			results.Baseline = report
 		}
 		
-		// Display results unless quiet mode is enabled
-		if !quiet {
-			displayResults(results)
+		// Drop fixed entries so that they cannot come back unnoticed. A
+		// baseline just written to the same file has none.
+		prune, _ := cmd.Flags().GetBool("baseline-prune")
+		if prune && baseline != nil && baselineWritePath != baselinePath && results.Baseline != nil && len(results.Baseline.Fixed) > 0 {
+			if err := baseline.Prune(results.Baseline).Save(baselinePath); err != nil {
+				return err
+			}
+			fmt.Fprintf(progress, "✂️  Removed %d fixed issue(s) from %s\n\n", countEntries(results.Baseline.Fixed), baselinePath)
+		}
+		
+		// Write the requested reports; the console output is one of them
+		// unless --quiet is set or another report goes to stdout
+		env := reportEnv{projectPath: projectPath, project: projectAnalyzer}
+		for _, output := range outputs {
+			if err := writeOutput(output, results, env); err != nil {
+				return fmt.Errorf("failed to write %s output: %w", output.format, err)
+			}
+			if !output.toStdout() {
+				fmt.Fprintf(progress, "📄 %s report written to %s\n", output.format, output.path)
+			}
+		}
+		
+		if watch {
+			interval, _ := cmd.Flags().GetDuration("watch-interval")
+			configFile, _ := cmd.Flags().GetString("config")
+			if configFile == "" {
+				configFile = filepath.Join(projectPath, analyzer.ConfigFileName)
+			}
+			// Besides the files analyzed, the files that change the results
+			watched := func() []string {
+				files := []string{configFile}
+				if config.KeyManifest != "" {
+					files = append(files, filepath.Join(projectPath, filepath.FromSlash(config.KeyManifest)))
+				}
+				if baselinePath != "" {
+					files = append(files, baselinePath)
+				}
+				return files
+			}
+			parserHash := analyzer.NewTranslationParser(config.ParserOptions()...).ConfigHash()
+			reanalyze := func(ctx context.Context) (*analyzer.AnalysisResult, error) {
+				reloaded, err := buildConfig()
+				if err != nil {
+					return nil, err
+				}
+				config = reloaded
+				projectAnalyzer.SetConfig(config)
+				// Parse results depend on the heuristics of the configuration
+				if hash := analyzer.NewTranslationParser(config.ParserOptions()...).ConfigHash(); hash != parserHash {
+					projectAnalyzer.SetCache(newCache(config))
+					parserHash = hash
+				}
+				if baselinePath != "" {
+					if baseline, err = analyzer.LoadBaseline(baselinePath); err != nil {
+						return nil, err
+					}
+				}
+				return analyze(ctx)
+			}
+			return watchProject(cmd.Context(), projectAnalyzer, results, interval, progress, watched, reanalyze)
+		}
+		
+		// Compared with a base revision, only new issues fail the run
+		fails := results.Fails(failOn)
+		if results.Diff != nil {
+			fails = results.Diff.Fails(failOn)
+		}
+		if fails {
+			return &ExitError{Code: 1}
 		}
 		
 		return nil
@@ -90,106 +358,198 @@ This command will:
 }
 
 func init() {
+	AnalyzeCmd.Flags().StringArray("output", nil, "Write a report as <format>:<path>, with - as path for stdout; repeat for several reports. Formats: "+strings.Join(reportFormats(), ", "))
 	AnalyzeCmd.Flags().Bool("report", false, "Generate a markdown report file")
 	AnalyzeCmd.Flags().String("report-file", "translations-report.md", "Custom filename for the markdown report (will be placed in reports/ folder)")
-	AnalyzeCmd.Flags().Bool("quiet", false, "Suppress console output (useful when generating reports)")
+	AnalyzeCmd.Flags().MarkDeprecated("report", "use --output md:<path> instead")
+	AnalyzeCmd.Flags().MarkDeprecated("report-file", "use --output md:<path> instead")
+	AnalyzeCmd.Flags().Bool("quiet", false, "Suppress the console output and progress messages; the exit code still reports issues")
+	AnalyzeCmd.Flags().IntP("jobs", "j", 0, "Number of source files to parse in parallel (0 uses one worker per CPU)")
+	AnalyzeCmd.Flags().Bool("staged", false, "Analyze the content staged in the git index and only report issues of staged files and changed message keys")
+	AnalyzeCmd.Flags().String("diff-base", "", "Compare HEAD with the given git revision and classify issues as new, fixed or unchanged; only new issues fail the run")
+	AnalyzeCmd.Flags().Bool("no-cache", false, "Parse every file instead of reusing results from "+analyzer.DefaultCacheDir)
+	AnalyzeCmd.Flags().String("config", "", "Configuration file (default: "+analyzer.ConfigFileName+" in the project root)")
+	AnalyzeCmd.Flags().String("fail-on", "error", "Lowest issue severity that fails the run: error, warning, info or off")
+	AnalyzeCmd.Flags().String("baseline", "", "Baseline file of known issues; only issues missing from it are reported and fail the run")
+	AnalyzeCmd.Flags().String("baseline-write", "", "Write the current issues to the given baseline file")
+	AnalyzeCmd.Flags().Bool("baseline-prune", false, "Remove fixed issues from the --baseline file")
+	AnalyzeCmd.Flags().Bool("watch", false, "Keep running and re-analyze when translation or source files change")
+	AnalyzeCmd.Flags().Duration("watch-interval", time.Second, "How often --watch polls the project for changes")
+	AnalyzeCmd.Flags().String("format", formatConsole, "Output format: "+strings.Join(outputFormats, ", "))
+	AnalyzeCmd.Flags().StringArray("include", nil, "Only analyze the source files matching the glob pattern; repeat for several patterns, added to \"include\" in the configuration")
+	AnalyzeCmd.Flags().StringArray("exclude", nil, "Skip the files and directories matching the glob pattern; repeat for several patterns, added to \"exclude\" in the configuration")
+	AnalyzeCmd.Flags().Bool("no-gitignore", false, "Also analyze the files ignored by .gitignore files and .git/info/exclude")
+	AnalyzeCmd.Flags().Bool("follow-symlinks", false, "Follow symbolic links to files and directories, which are skipped by default")
+	AnalyzeCmd.Flags().Float64("min-confidence", analyzer.DefaultMinConfidence, "Lowest confidence (0-1) of reported hardcoded strings")
+	AnalyzeCmd.Flags().Duration("timeout", 0, "Abort the analysis after the given duration (e.g. 30s, 2m); 0 means no timeout")
 }
 
 
 
-func displayResults(results *analyzer.AnalysisResult) {
-	fmt.Println("=== Next-intl Translation Analysis ===")
-	fmt.Println()
+// loadConfig reads the file given with --config, or the project's
+// configuration file when there is one. projectFS is set when the project is
+// not read from the directory at projectPath.
+func loadConfig(cmd *cobra.Command, projectPath string, projectFS fs.FS) (*analyzer.Config, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath != "" {
+		return analyzer.LoadConfig(configPath)
+	}
+	if projectFS != nil {
+		return analyzer.LoadProjectConfigFS(projectFS)
+	}
+	return analyzer.LoadProjectConfig(projectPath)
+}
+
+// baselineFlagsSet reports whether any of the baseline flags was given
+func baselineFlagsSet(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("baseline") || cmd.Flags().Changed("baseline-write") || cmd.Flags().Changed("baseline-prune")
+}
+
+// severityTag labels issues that are not errors, so that the default output
+// stays unchanged
+func severityTag(severity analyzer.Severity) string {
+	if severity == analyzer.SeverityError {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", severity)
+}
+
+// displayDiagnostics prints the problems the analysis ran into to stderr, so
+// that they never mix with structured output on stdout
+func displayDiagnostics(diagnostics []analyzer.Diagnostic) {
+	for _, diagnostic := range diagnostics {
+		label := "Warning"
+		if diagnostic.Severity == analyzer.SeverityError {
+			label = "Error"
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s\n", label, diagnostic)
+	}
+}
+
+// displayResults writes the console output of results to w
+func displayResults(w io.Writer, results *analyzer.AnalysisResult) {
+	fmt.Fprintln(w, "=== Next-intl Translation Analysis ===")
+	fmt.Fprintln(w)
 	
-	fmt.Printf("📊 Overall Summary:\n")
-	fmt.Printf("   Total translations: %d\n", results.TotalTranslations)
-	fmt.Printf("   Used translations: %d\n", results.UsedTranslations)
-	fmt.Printf("   Unused translations: %d\n", len(results.UnusedTranslations))
-	fmt.Printf("   Undeclared translations: %d\n", len(results.UndeclaredTranslations))
-	fmt.Printf("   Hardcoded strings: %d\n", len(results.HardcodedStrings))
-	fmt.Printf("   Locales analyzed: %d\n", len(results.LocaleResults))
-	fmt.Println()
+	fmt.Fprintf(w, "📊 Overall Summary:\n")
+	fmt.Fprintf(w, "   Total translations: %d\n", results.TotalTranslations)
+	fmt.Fprintf(w, "   Used translations: %d\n", results.UsedTranslations)
+	fmt.Fprintf(w, "   Unused translations: %d\n", len(results.UnusedTranslations))
+	fmt.Fprintf(w, "   Undeclared translations: %d\n", len(results.UndeclaredTranslations))
+	fmt.Fprintf(w, "   Hardcoded strings: %d\n", len(results.HardcodedStrings))
+	fmt.Fprintf(w, "   Locales analyzed: %d\n", len(results.LocaleResults))
+	counts := results.CountBySeverity()
+	fmt.Fprintf(w, "   Errors: %d, warnings: %d, info: %d\n", counts[analyzer.SeverityError], counts[analyzer.SeverityWarning], counts[analyzer.SeverityInfo])
+	fmt.Fprintln(w)
+	
+	displayWorkspace(w, results)
+	
+	if results.Baseline != nil {
+		fmt.Fprintf(w, "📌 Baseline:\n")
+		fmt.Fprintf(w, "   Known issues suppressed: %d\n", results.Baseline.Suppressed)
+		fmt.Fprintf(w, "   Fixed since baseline: %d\n", countEntries(results.Baseline.Fixed))
+		for _, entry := range results.Baseline.Fixed {
+			fmt.Fprintf(w, "   ✅ [%s] %s (in %s)\n", entry.Rule, entry.Key, entry.File)
+		}
+		if len(results.Baseline.Fixed) > 0 {
+			fmt.Fprintln(w, "   Run with --baseline-prune to remove fixed issues from the baseline")
+		}
+		fmt.Fprintln(w)
+	}
 	
 	if len(results.LocaleResults) > 0 {
-		fmt.Println("🌍 Per-locale Analysis:")
-		fmt.Println()
+		fmt.Fprintln(w, "🌍 Per-locale Analysis:")
+		fmt.Fprintln(w)
 		
-		for locale, localeResult := range results.LocaleResults {
-			fmt.Printf("   📍 %s:\n", strings.ToUpper(locale))
-			fmt.Printf("      Total translations: %d\n", localeResult.TotalTranslations)
-			fmt.Printf("      Used translations: %d\n", localeResult.UsedTranslations)
-			fmt.Printf("      Unused translations: %d\n", len(localeResult.UnusedTranslations))
-			fmt.Printf("      Undeclared translations: %d\n", len(localeResult.UndeclaredTranslations))
+		for _, locale := range results.Locales() {
+			localeResult := results.LocaleResults[locale]
+			fmt.Fprintf(w, "   📍 %s:\n", strings.ToUpper(locale))
+			fmt.Fprintf(w, "      Total translations: %d\n", localeResult.TotalTranslations)
+			fmt.Fprintf(w, "      Used translations: %d\n", localeResult.UsedTranslations)
+			fmt.Fprintf(w, "      Unused translations: %d\n", len(localeResult.UnusedTranslations))
+			fmt.Fprintf(w, "      Undeclared translations: %d\n", len(localeResult.UndeclaredTranslations))
 			
 			if len(localeResult.UnusedTranslations) > 0 {
-				fmt.Printf("      ❌ Unused in %s:\n", strings.ToUpper(locale))
+				fmt.Fprintf(w, "      ❌ Unused in %s:\n", strings.ToUpper(locale))
 				for _, translation := range localeResult.UnusedTranslations {
-					fmt.Printf("         - %s (in %s)\n", translation.Key, translation.File)
+					fmt.Fprintf(w, "         - %s (in %s)\n", translation.Key, translation.File)
 				}
 			}
 			
 			if len(localeResult.UndeclaredTranslations) > 0 {
-				fmt.Printf("      ⚠️  Undeclared in %s:\n", strings.ToUpper(locale))
+				fmt.Fprintf(w, "      ⚠️  Undeclared in %s:\n", strings.ToUpper(locale))
 				for _, translation := range localeResult.UndeclaredTranslations {
-					fmt.Printf("         - %s (used in %s:%d)\n", translation.Key, translation.File, translation.Line)
+					fmt.Fprintf(w, "         - %s (used in %s:%d)\n", translation.Key, translation.File, translation.Line)
 				}
 			}
 			
 			// Removed per-locale hardcoded strings section as they are now handled globally
 			
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
 	}
 	
 	if len(results.UnusedTranslations) > 0 {
-		fmt.Printf("❌ Overall unused translations (%d):\n", len(results.UnusedTranslations))
+		fmt.Fprintf(w, "❌ Overall unused translations (%d):\n", len(results.UnusedTranslations))
 		for _, translation := range results.UnusedTranslations {
-			fmt.Printf("   - %s (in %s, locale: %s)\n", translation.Key, translation.File, translation.Locale)
+			fmt.Fprintf(w, "   - %s (in %s, locale: %s)%s\n", translation.Key, translation.File, translation.Locale, severityTag(translation.Severity))
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	} else {
-		fmt.Println("✅ No unused translations found!")
-		fmt.Println()
+		fmt.Fprintln(w, "✅ No unused translations found!")
+		fmt.Fprintln(w)
 	}
 	
 	if len(results.UndeclaredTranslations) > 0 {
-		fmt.Printf("⚠️  Overall undeclared translations (%d):\n", len(results.UndeclaredTranslations))
+		fmt.Fprintf(w, "⚠️  Overall undeclared translations (%d):\n", len(results.UndeclaredTranslations))
 		for _, translation := range results.UndeclaredTranslations {
-			fmt.Printf("   - %s (used in %s:%d, locale: %s)\n", translation.Key, translation.File, translation.Line, translation.Locale)
+			fmt.Fprintf(w, "   - %s (used in %s:%d, locale: %s)%s\n", translation.Key, translation.File, translation.Line, translation.Locale, severityTag(translation.Severity))
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	} else {
-		fmt.Println("✅ No undeclared translations found!")
-		fmt.Println()
+		fmt.Fprintln(w, "✅ No undeclared translations found!")
+		fmt.Fprintln(w)
 	}
 	
 	if len(results.HardcodedStrings) > 0 {
-		fmt.Printf("🔤 Hardcoded strings (%d):\n", len(results.HardcodedStrings))
+		fmt.Fprintf(w, "🔤 Hardcoded strings (%d):\n", len(results.HardcodedStrings))
 		for _, translation := range results.HardcodedStrings {
-			fmt.Printf("   - %s (used in %s:%d)\n", translation.Key, translation.File, translation.Line)
+			fmt.Fprintf(w, "   - %s (used in %s:%d)%s\n", translation.Key, translation.File, translation.Line, severityTag(translation.Severity))
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	} else {
-		fmt.Println("✅ No hardcoded strings found!")
-		fmt.Println()
+		fmt.Fprintln(w, "✅ No hardcoded strings found!")
+		fmt.Fprintln(w)
 	}
 	
-	if len(results.UnusedTranslations) > 0 || len(results.UndeclaredTranslations) > 0 || len(results.HardcodedStrings) > 0 {
-		os.Exit(1)
+	if len(results.MissingTranslations) > 0 {
+		fmt.Fprintf(w, "🧩 Translations missing in some locales (%d):\n", len(results.MissingTranslations))
+		for _, translation := range results.MissingTranslations {
+			fmt.Fprintf(w, "   - %s (declared in %s:%d, missing for locale: %s)%s\n", translation.Key, translation.File, translation.Line, translation.Locale, severityTag(translation.Severity))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(results.UnusedSuppressions) > 0 {
+		fmt.Fprintf(w, "🔕 Unused suppression comments (%d):\n", len(results.UnusedSuppressions))
+		for _, translation := range results.UnusedSuppressions {
+			fmt.Fprintf(w, "   - %s (in %s:%d)%s\n", translation.Key, translation.File, translation.Line, severityTag(translation.Severity))
+		}
+		fmt.Fprintln(w)
 	}
 }
 
-// generateMarkdownReport creates a detailed markdown report of the analysis results
-func generateMarkdownReport(results *analyzer.AnalysisResult, projectPath string, reportPath string, cmd *cobra.Command) error {
-	// Create reports directory in the project path
-	reportsDir := filepath.Join(projectPath, "reports")
-	if err := os.MkdirAll(reportsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create reports directory: %w", err)
+// countEntries returns the number of issue occurrences in baseline entries
+func countEntries(entries []analyzer.BaselineEntry) int {
+	count := 0
+	for _, entry := range entries {
+		count += entry.Count
 	}
-	
-	// Create the full path for the report file
-	fullReportPath := filepath.Join(reportsDir, reportPath)
-	
+	return count
+}
+
+// markdownReport returns a detailed markdown report of the analysis results
+func markdownReport(results *analyzer.AnalysisResult, projectPath string) string {
 	content := fmt.Sprintf(`# Next-intl Translation Analysis Report
 
 **Generated:** %s  
@@ -206,12 +566,13 @@ func generateMarkdownReport(results *analyzer.AnalysisResult, projectPath string
 | Hardcoded Strings | %d |
 | Locales Analyzed | %d |
 
-## 🌍 Per-locale Analysis
+%s%s%s## 🌍 Per-locale Analysis
 
-`, time.Now().Format("2006-01-02 15:04:05"), projectPath, results.TotalTranslations, results.UsedTranslations, len(results.UnusedTranslations), len(results.UndeclaredTranslations), len(results.HardcodedStrings), len(results.LocaleResults))
+`, reportTime().Format("2006-01-02 15:04:05"), projectPath, results.TotalTranslations, results.UsedTranslations, len(results.UnusedTranslations), len(results.UndeclaredTranslations), len(results.HardcodedStrings), len(results.LocaleResults), markdownWorkspaceSection(results), markdownBaselineSection(results.Baseline), markdownDiffSection(results.Diff))
 
 	// Add per-locale results
-	for locale, localeResult := range results.LocaleResults {
+	for _, locale := range results.Locales() {
+		localeResult := results.LocaleResults[locale]
 		content += fmt.Sprintf("### 📍 %s\n\n", strings.ToUpper(locale))
 		content += fmt.Sprintf("| Metric | Count |\n")
 		content += fmt.Sprintf("|--------|-------|\n")
@@ -248,10 +609,10 @@ func generateMarkdownReport(results *analyzer.AnalysisResult, projectPath string
 	// Add overall unused translations
 	if len(results.UnusedTranslations) > 0 {
 		content += "## ❌ Overall Unused Translations\n\n"
-		content += "| Key | File | Locale |\n"
-		content += "|-----|------|--------|\n"
+		content += "| Key | File | Locale | Severity |\n"
+		content += "|-----|------|--------|----------|\n"
 		for _, translation := range results.UnusedTranslations {
-			content += fmt.Sprintf("| `%s` | `%s` | %s |\n", translation.Key, translation.File, translation.Locale)
+			content += fmt.Sprintf("| `%s` | `%s` | %s | %s |\n", translation.Key, translation.File, translation.Locale, translation.Severity)
 		}
 		content += "\n"
 	} else {
@@ -261,10 +622,10 @@ func generateMarkdownReport(results *analyzer.AnalysisResult, projectPath string
 	// Add overall undeclared translations
 	if len(results.UndeclaredTranslations) > 0 {
 		content += "## ⚠️ Overall Undeclared Translations\n\n"
-		content += "| Key | File | Line | Locale |\n"
-		content += "|-----|------|------|--------|\n"
+		content += "| Key | File | Line | Locale | Severity |\n"
+		content += "|-----|------|------|--------|----------|\n"
 		for _, translation := range results.UndeclaredTranslations {
-			content += fmt.Sprintf("| `%s` | `%s` | %d | %s |\n", translation.Key, translation.File, translation.Line, translation.Locale)
+			content += fmt.Sprintf("| `%s` | `%s` | %d | %s | %s |\n", translation.Key, translation.File, translation.Line, translation.Locale, translation.Severity)
 		}
 		content += "\n"
 	} else {
@@ -274,15 +635,37 @@ func generateMarkdownReport(results *analyzer.AnalysisResult, projectPath string
 	// Add overall hardcoded strings
 	if len(results.HardcodedStrings) > 0 {
 		content += "## 🔤 Hardcoded Strings\n\n"
-		content += "| Text | File | Line |\n"
-		content += "|------|------|------|\n"
+		content += "| Text | File | Line | Severity |\n"
+		content += "|------|------|------|----------|\n"
 		for _, translation := range results.HardcodedStrings {
-			content += fmt.Sprintf("| `%s` | `%s` | %d |\n", translation.Key, translation.File, translation.Line)
+			content += fmt.Sprintf("| `%s` | `%s` | %d | %s |\n", translation.Key, translation.File, translation.Line, translation.Severity)
 		}
 		content += "\n"
 	} else {
 		content += "## ✅ No Hardcoded Strings Found\n\n"
 	}
+	
+	// Add keys missing in some locales
+	if len(results.MissingTranslations) > 0 {
+		content += "## 🧩 Translations Missing in Some Locales\n\n"
+		content += "| Key | Declared in | Line | Missing for | Severity |\n"
+		content += "|-----|-------------|------|-------------|----------|\n"
+		for _, translation := range results.MissingTranslations {
+			content += fmt.Sprintf("| `%s` | `%s` | %d | %s | %s |\n", translation.Key, translation.File, translation.Line, translation.Locale, translation.Severity)
+		}
+		content += "\n"
+	}
+
+	// Add unused suppression comments
+	if len(results.UnusedSuppressions) > 0 {
+		content += "## 🔕 Unused Suppression Comments\n\n"
+		content += "| Directive | File | Line | Severity |\n"
+		content += "|-----------|------|------|----------|\n"
+		for _, translation := range results.UnusedSuppressions {
+			content += fmt.Sprintf("| `%s` | `%s` | %d | %s |\n", translation.Key, translation.File, translation.Line, translation.Severity)
+		}
+		content += "\n"
+	}
 
 	// Add recommendations
 	content += `## 💡 Recommendations
@@ -302,6 +685,10 @@ func generateMarkdownReport(results *analyzer.AnalysisResult, projectPath string
 - Create appropriate entries in your translation files
 - Use the t() function or appropriate hooks to translate these strings
 
+### For Missing Translations:
+- Translate the keys for the locales that lack them
+- Remove keys that are no longer needed from the locales that still declare them
+
 ### Best Practices:
 - Regularly run this analysis to maintain clean translation files
 - Use consistent naming conventions for translation keys
@@ -312,16 +699,28 @@ func generateMarkdownReport(results *analyzer.AnalysisResult, projectPath string
 
 *Report generated by next-intl-analyzer*
 `
+	return content
+}
 
-	// Write the report to file
-	if err := os.WriteFile(fullReportPath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write report file: %w", err)
+// markdownBaselineSection describes the applied baseline, if any
+func markdownBaselineSection(report *analyzer.BaselineReport) string {
+	if report == nil {
+		return ""
 	}
-
-	// Only print success message if not in quiet mode
-	quiet, _ := cmd.Flags().GetBool("quiet")
-	if !quiet {
-		fmt.Printf("📄 Markdown report generated: %s\n", fullReportPath)
+	
+	content := "## 📌 Baseline\n\n"
+	content += "| Metric | Count |\n"
+	content += "|--------|-------|\n"
+	content += fmt.Sprintf("| Known Issues Suppressed | %d |\n", report.Suppressed)
+	content += fmt.Sprintf("| Fixed Since Baseline | %d |\n\n", countEntries(report.Fixed))
+	
+	if len(report.Fixed) > 0 {
+		content += "| Rule | Key | File |\n"
+		content += "|------|-----|------|\n"
+		for _, entry := range report.Fixed {
+			content += fmt.Sprintf("| %s | `%s` | `%s` |\n", entry.Rule, entry.Key, entry.File)
+		}
+		content += "\n"
 	}
-	return nil
-} 
\ No newline at end of file
+	return content
+}
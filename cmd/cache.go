@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"next-intl-analyzer/pkg/analyzer"
+
+	"github.com/spf13/cobra"
+)
+
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the parse cache",
+	Long: `Manage the on-disk parse cache that analyze keeps in
+` + analyzer.DefaultCacheDir + `/ inside the analyzed project.`,
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean [project-path]",
+	Short: "Remove the parse cache of a project",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := "."
+		if len(args) > 0 {
+			projectPath = args[0]
+		}
+
+		if err := analyzer.CleanCache(projectPath); err != nil {
+			return fmt.Errorf("failed to clean cache: %w", err)
+		}
+
+		fmt.Printf("🧹 Removed %s\n", filepath.Join(projectPath, analyzer.DefaultCacheDir))
+		return nil
+	},
+}
+
+func init() {
+	CacheCmd.AddCommand(cacheCleanCmd)
+}
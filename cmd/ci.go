@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+// CI integrations: GitHub Actions workflow commands, which annotate the
+// diff of a pull request, and GitLab Code Quality reports
+
+// detectFormat resolves --format auto to the format of the CI service the
+// analyzer runs in, as told by the environment, or to the console format
+func detectFormat(getenv func(string) string) string {
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		return formatGitHub
+	case getenv("GITLAB_CI") == "true":
+		return formatGitLab
+	}
+	return formatConsole
+}
+
+// repositoryRoot returns the directory that annotations and Code Quality
+// reports are relative to: the checkout of the CI job, as told by the
+// environment, else the root of the git repository of the working directory.
+// Jobs may run in a subdirectory, with working-directory for instance.
+func repositoryRoot(getenv func(string) string) string {
+	for _, name := range []string{"GITHUB_WORKSPACE", "CI_PROJECT_DIR"} {
+		if dir := getenv(name); dir != "" {
+			return dir
+		}
+	}
+	if output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		return filepath.FromSlash(strings.TrimSpace(string(output)))
+	}
+	wd, _ := os.Getwd()
+	return wd
+}
+
+// repositoryPath returns file relative to the repository root with forward
+// slashes, or file itself when it is outside the repository
+func repositoryPath(root string, file string) string {
+	abs, err := filepath.Abs(file)
+	if err != nil || root == "" {
+		return filepath.ToSlash(file)
+	}
+	// The root given by git has its symbolic links resolved
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	if rel, err := filepath.Rel(root, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(file)
+}
+
+// fingerprints hands out fingerprints that are unique within a report: the
+// issue fingerprint, suffixed for further issues sharing it
+type fingerprints struct {
+	projectPath string
+	seen        map[string]int
+}
+
+func newFingerprints(projectPath string) *fingerprints {
+	return &fingerprints{projectPath: projectPath, seen: make(map[string]int)}
+}
+
+func (f *fingerprints) next(issue analyzer.Issue) string {
+	fingerprint := analyzer.Fingerprint(issue, f.projectPath)
+	f.seen[fingerprint]++
+	if count := f.seen[fingerprint]; count > 1 {
+		return fmt.Sprintf("%s-%d", fingerprint, count)
+	}
+	return fingerprint
+}
+
+// githubLevel maps a severity to a workflow command
+func githubLevel(severity analyzer.Severity) string {
+	switch severity {
+	case analyzer.SeverityError:
+		return "error"
+	case analyzer.SeverityWarning:
+		return "warning"
+	}
+	return "notice"
+}
+
+// githubEscaper escapes the message of a workflow command, and
+// githubPropertyEscaper its properties
+var (
+	githubEscaper         = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// writeGitHubAnnotations writes a workflow command per issue, which GitHub
+// Actions shows as an annotation on the line of the issue. Compared with a
+// base revision, only new issues are annotated.
+func writeGitHubAnnotations(w io.Writer, results *analyzer.AnalysisResult) error {
+	issues := results.Issues()
+	if results.Diff != nil {
+		issues = results.Diff.New
+	}
+	root := repositoryRoot(os.Getenv)
+	for _, issue := range issues {
+		properties := "file=" + githubPropertyEscaper.Replace(repositoryPath(root, issue.File))
+		if issue.Line > 0 {
+			properties += fmt.Sprintf(",line=%d", issue.Line)
+		}
+		properties += ",title=" + githubPropertyEscaper.Replace(issue.Rule)
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", githubLevel(issue.Severity), properties, githubEscaper.Replace(issueMessage(issue))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitlabIssue is an entry of a GitLab Code Quality report
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+}
+
+// gitlabSeverity maps a severity to a Code Quality severity
+func gitlabSeverity(severity analyzer.Severity) string {
+	switch severity {
+	case analyzer.SeverityError:
+		return "major"
+	case analyzer.SeverityWarning:
+		return "minor"
+	}
+	return "info"
+}
+
+// newGitLabReport lists every issue: GitLab compares the fingerprints with
+// the report of the target branch itself
+func newGitLabReport(results *analyzer.AnalysisResult, projectPath string) []gitlabIssue {
+	report := make([]gitlabIssue, 0)
+	fingerprints := newFingerprints(projectPath)
+	root := repositoryRoot(os.Getenv)
+	for _, issue := range results.Issues() {
+		// Code Quality requires a line; issues of whole files are on the first
+		line := issue.Line
+		if line <= 0 {
+			line = 1
+		}
+		report = append(report, gitlabIssue{
+			Description: issueMessage(issue),
+			CheckName:   issue.Rule,
+			Fingerprint: fingerprints.next(issue),
+			Severity:    gitlabSeverity(issue.Severity),
+			Location:    gitlabLocation{Path: repositoryPath(root, issue.File), Lines: gitlabLines{Begin: line}},
+		})
+	}
+	return report
+}
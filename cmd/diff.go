@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+// diffHead is the revision compared with --diff-base
+const diffHead = "HEAD"
+
+// analyzeRevision analyzes the project at projectPath as of the git revision
+// rev and returns the results with the declared keys of every locale
+func analyzeRevision(ctx context.Context, projectPath string, rev string, opts ...analyzer.Option) (*analyzer.AnalysisResult, map[string]map[string]analyzer.Translation, error) {
+	fsys, err := analyzer.GitRevisionFS(ctx, projectPath, rev)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read revision %s: %w", rev, err)
+	}
+	revisionAnalyzer, err := analyzer.New(append(opts, analyzer.WithDir(projectPath), analyzer.WithFS(fsys))...)
+	if err != nil {
+		return nil, nil, err
+	}
+	results, err := revisionAnalyzer.Analyze(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("analysis of revision %s failed: %w", rev, err)
+	}
+	messages, err := revisionAnalyzer.Messages()
+	if err != nil {
+		return nil, nil, fmt.Errorf("analysis of revision %s failed: %w", rev, err)
+	}
+	return results, messages, nil
+}
+
+// displayRevisionDiff writes the changes between the base revision and HEAD
+// to w
+func displayRevisionDiff(w io.Writer, diff *analyzer.RevisionDiff) {
+	fmt.Fprintln(w, "=== Next-intl Translation Changes ===")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "🔀 %s..%s:\n", diff.Base, diff.Head)
+	fmt.Fprintf(w, "   New issues: %d\n", len(diff.New))
+	fmt.Fprintf(w, "   Fixed issues: %d\n", len(diff.Fixed))
+	fmt.Fprintf(w, "   Unchanged issues: %d\n", len(diff.Unchanged))
+	fmt.Fprintf(w, "   Keys added: %d, removed: %d, renamed: %d\n", len(diff.AddedKeys), len(diff.RemovedKeys), len(diff.RenamedKeys))
+	fmt.Fprintln(w)
+
+	if len(diff.AddedKeys) > 0 {
+		fmt.Fprintf(w, "➕ Added keys (%d):\n", len(diff.AddedKeys))
+		for _, change := range diff.AddedKeys {
+			fmt.Fprintf(w, "   + %s (%s)\n", change.Key, strings.Join(change.Locales, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+	if len(diff.RemovedKeys) > 0 {
+		fmt.Fprintf(w, "➖ Removed keys (%d):\n", len(diff.RemovedKeys))
+		for _, change := range diff.RemovedKeys {
+			fmt.Fprintf(w, "   - %s (%s)\n", change.Key, strings.Join(change.Locales, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+	if len(diff.RenamedKeys) > 0 {
+		fmt.Fprintf(w, "✏️  Renamed keys (%d):\n", len(diff.RenamedKeys))
+		for _, rename := range diff.RenamedKeys {
+			fmt.Fprintf(w, "   ~ %s → %s (%s)\n", rename.From, rename.To, strings.Join(rename.Locales, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(diff.New) > 0 {
+		fmt.Fprintf(w, "🆕 New issues (%d):\n", len(diff.New))
+		for _, issue := range diff.New {
+			fmt.Fprintf(w, "   + %s\n", formatIssue(issue))
+		}
+		fmt.Fprintln(w)
+	} else {
+		fmt.Fprintln(w, "✅ No new issues!")
+		fmt.Fprintln(w)
+	}
+	if len(diff.Fixed) > 0 {
+		fmt.Fprintf(w, "✅ Fixed issues (%d):\n", len(diff.Fixed))
+		for _, issue := range diff.Fixed {
+			fmt.Fprintf(w, "   - %s\n", formatIssue(issue))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// markdownDiffSection describes the changes since the base revision, if the
+// results were compared with one
+func markdownDiffSection(diff *analyzer.RevisionDiff) string {
+	if diff == nil {
+		return ""
+	}
+
+	content := fmt.Sprintf("## 🔀 Changes (%s..%s)\n\n", diff.Base, diff.Head)
+	content += "| Metric | Count |\n"
+	content += "|--------|-------|\n"
+	content += fmt.Sprintf("| New Issues | %d |\n", len(diff.New))
+	content += fmt.Sprintf("| Fixed Issues | %d |\n", len(diff.Fixed))
+	content += fmt.Sprintf("| Unchanged Issues | %d |\n", len(diff.Unchanged))
+	content += fmt.Sprintf("| Added Keys | %d |\n", len(diff.AddedKeys))
+	content += fmt.Sprintf("| Removed Keys | %d |\n", len(diff.RemovedKeys))
+	content += fmt.Sprintf("| Renamed Keys | %d |\n\n", len(diff.RenamedKeys))
+
+	if len(diff.AddedKeys)+len(diff.RemovedKeys)+len(diff.RenamedKeys) > 0 {
+		content += "### Key Changes\n\n"
+		content += "| Change | Key | Locales |\n"
+		content += "|--------|-----|---------|\n"
+		for _, change := range diff.AddedKeys {
+			content += fmt.Sprintf("| added | `%s` | %s |\n", change.Key, strings.Join(change.Locales, ", "))
+		}
+		for _, change := range diff.RemovedKeys {
+			content += fmt.Sprintf("| removed | `%s` | %s |\n", change.Key, strings.Join(change.Locales, ", "))
+		}
+		for _, rename := range diff.RenamedKeys {
+			content += fmt.Sprintf("| renamed | `%s` → `%s` | %s |\n", rename.From, rename.To, strings.Join(rename.Locales, ", "))
+		}
+		content += "\n"
+	}
+
+	for _, group := range []struct {
+		title  string
+		issues []analyzer.Issue
+	}{{"New Issues", diff.New}, {"Fixed Issues", diff.Fixed}} {
+		if len(group.issues) == 0 {
+			continue
+		}
+		content += fmt.Sprintf("### %s\n\n", group.title)
+		content += "| Rule | Key | File | Line | Locale |\n"
+		content += "|------|-----|------|------|--------|\n"
+		for _, issue := range group.issues {
+			content += fmt.Sprintf("| %s | `%s` | `%s` | %d | %s |\n", issue.Rule, issue.Key, issue.File, issue.Line, issue.Locale)
+		}
+		content += "\n"
+	}
+	return content
+}
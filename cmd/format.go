@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+// Output formats of the analyze command. Structured formats are written to
+// stdout in place of the console output.
+const (
+	formatConsole    = "console"
+	formatJSON       = "json"
+	formatSARIF      = "sarif"
+	formatHTML       = "html"
+	formatJUnit      = "junit"
+	formatCheckstyle = "checkstyle"
+	formatGitHub     = "github"
+	formatGitLab     = "gitlab"
+	// formatAuto picks the format of the CI service, see detectFormat
+	formatAuto = "auto"
+)
+
+var outputFormats = []string{formatConsole, formatJSON, formatSARIF, formatHTML, formatJUnit, formatCheckstyle, formatGitHub, formatGitLab, formatAuto}
+
+func validateFormat(format string) error {
+	for _, known := range outputFormats {
+		if format == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format %q (expected %s)", format, strings.Join(outputFormats, ", "))
+}
+
+// reportTime returns the generation time written into reports. Like other
+// reproducible build tools, it honors SOURCE_DATE_EPOCH, so that reports can
+// be compared byte for byte.
+func reportTime() time.Time {
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if seconds, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC()
+		}
+	}
+	return time.Now()
+}
+
+// issueMessage describes an issue in one sentence
+func issueMessage(issue analyzer.Issue) string {
+	switch issue.Rule {
+	case analyzer.RuleUnusedKey:
+		return fmt.Sprintf("Translation key %q is declared for locale %s but never used", issue.Key, issue.Locale)
+	case analyzer.RuleUndeclaredKey:
+		return fmt.Sprintf("Translation key %q is used but not declared for locale %s", issue.Key, issue.Locale)
+	case analyzer.RuleHardcodedString:
+		return fmt.Sprintf("Hardcoded string %q should be translated", issue.Key)
+	case analyzer.RuleMissingTranslation:
+		return fmt.Sprintf("Translation key %q is declared for other locales but missing for locale %s", issue.Key, issue.Locale)
+	case analyzer.RuleUnusedSuppression:
+		return fmt.Sprintf("Suppression comment %q does not silence any issue", issue.Key)
+	}
+	return issue.Key
+}
+
+// projectRelativePath returns file relative to projectPath with forward
+// slashes, so that structured reports do not depend on the working directory
+func projectRelativePath(projectPath string, file string) string {
+	if rel, err := filepath.Rel(projectPath, file); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(file)
+}
+
+// jsonReport is the document written by --format json
+type jsonReport struct {
+	Tool    string      `json:"tool"`
+	Version string      `json:"version"`
+	Summary jsonSummary `json:"summary"`
+	Issues  []jsonIssue `json:"issues"`
+	// Diff is only set with --diff-base
+	Diff *jsonDiff `json:"diff,omitempty"`
+	// Workspace is only set when the configuration defines projects
+	Workspace *jsonWorkspace `json:"workspace,omitempty"`
+}
+
+type jsonSummary struct {
+	TotalTranslations      int `json:"totalTranslations"`
+	UsedTranslations       int `json:"usedTranslations"`
+	UnusedTranslations     int `json:"unusedTranslations"`
+	UndeclaredTranslations int `json:"undeclaredTranslations"`
+	HardcodedStrings       int `json:"hardcodedStrings"`
+	MissingTranslations    int `json:"missingTranslations"`
+	UnusedSuppressions     int `json:"unusedSuppressions"`
+	BaselineSuppressed     int `json:"baselineSuppressed,omitempty"`
+}
+
+type jsonIssue struct {
+	Rule        string            `json:"rule"`
+	Severity    analyzer.Severity `json:"severity"`
+	Message     string            `json:"message"`
+	File        string            `json:"file"`
+	Line        int               `json:"line,omitempty"`
+	Locale      string            `json:"locale,omitempty"`
+	Key         string            `json:"key"`
+	Fingerprint string            `json:"fingerprint"`
+	// Status is only set with --diff-base
+	Status analyzer.IssueStatus `json:"status,omitempty"`
+	// Confidence and Signals are only set for hardcoded strings
+	Confidence *float64          `json:"confidence,omitempty"`
+	Signals    []analyzer.Signal `json:"signals,omitempty"`
+}
+
+// jsonDiff compares the reported issues, those of HEAD, with a base revision
+type jsonDiff struct {
+	Base        string               `json:"base"`
+	Head        string               `json:"head"`
+	New         int                  `json:"new"`
+	Fixed       int                  `json:"fixed"`
+	Unchanged   int                  `json:"unchanged"`
+	AddedKeys   []analyzer.KeyChange `json:"addedKeys"`
+	RemovedKeys []analyzer.KeyChange `json:"removedKeys"`
+	RenamedKeys []analyzer.KeyRename `json:"renamedKeys"`
+	// FixedIssues lists the issues of the base revision that HEAD is rid of
+	FixedIssues []jsonIssue `json:"fixedIssues"`
+}
+
+func newJSONReport(results *analyzer.AnalysisResult, projectPath string) *jsonReport {
+	report := &jsonReport{
+		Tool:    "next-intl-analyzer",
+		Version: analyzer.Version,
+		Summary: jsonSummary{
+			TotalTranslations:      results.TotalTranslations,
+			UsedTranslations:       results.UsedTranslations,
+			UnusedTranslations:     len(results.UnusedTranslations),
+			UndeclaredTranslations: len(results.UndeclaredTranslations),
+			HardcodedStrings:       len(results.HardcodedStrings),
+			MissingTranslations:    len(results.MissingTranslations),
+			UnusedSuppressions:     len(results.UnusedSuppressions),
+		},
+		Issues: make([]jsonIssue, 0),
+	}
+	if results.Baseline != nil {
+		report.Summary.BaselineSuppressed = results.Baseline.Suppressed
+	}
+
+	for _, issue := range results.Issues() {
+		report.Issues = append(report.Issues, newJSONIssue(issue, results.Diff, projectPath))
+	}
+
+	if diff := results.Diff; diff != nil {
+		report.Diff = &jsonDiff{
+			Base:        diff.Base,
+			Head:        diff.Head,
+			New:         len(diff.New),
+			Fixed:       len(diff.Fixed),
+			Unchanged:   len(diff.Unchanged),
+			AddedKeys:   append([]analyzer.KeyChange{}, diff.AddedKeys...),
+			RemovedKeys: append([]analyzer.KeyChange{}, diff.RemovedKeys...),
+			RenamedKeys: append([]analyzer.KeyRename{}, diff.RenamedKeys...),
+			FixedIssues: make([]jsonIssue, 0, len(diff.Fixed)),
+		}
+		for _, issue := range diff.Fixed {
+			report.Diff.FixedIssues = append(report.Diff.FixedIssues, newJSONIssue(issue, diff, projectPath))
+		}
+	}
+	if results.Workspace != nil {
+		report.Workspace = newJSONWorkspace(results)
+	}
+	return report
+}
+
+func newJSONIssue(issue analyzer.Issue, diff *analyzer.RevisionDiff, projectPath string) jsonIssue {
+	entry := jsonIssue{
+		Rule:        issue.Rule,
+		Severity:    issue.Severity,
+		Message:     issueMessage(issue),
+		File:        projectRelativePath(projectPath, issue.File),
+		Line:        issue.Line,
+		Locale:      issue.Locale,
+		Key:         issue.Key,
+		Fingerprint: analyzer.Fingerprint(issue, projectPath),
+	}
+	if diff != nil {
+		entry.Status = diff.Status(issue)
+	}
+	if issue.Rule == analyzer.RuleHardcodedString {
+		confidence := issue.Confidence
+		entry.Confidence = &confidence
+		entry.Signals = issue.Signals
+	}
+	return entry
+}
+
+// SARIF 2.1.0 log, limited to the properties the analyzer fills in
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+	// Properties is only set with --diff-base
+	Properties *sarifRunProperties `json:"properties,omitempty"`
+}
+
+// sarifRunProperties records the base revision and the key changes since
+type sarifRunProperties struct {
+	Base        string               `json:"base"`
+	Head        string               `json:"head"`
+	AddedKeys   []analyzer.KeyChange `json:"addedKeys"`
+	RemovedKeys []analyzer.KeyChange `json:"removedKeys"`
+	RenamedKeys []analyzer.KeyRename `json:"renamedKeys"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	// BaselineState is only set with --diff-base
+	BaselineState string           `json:"baselineState,omitempty"`
+	Properties    *sarifProperties `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifProperties struct {
+	Locale     string            `json:"locale,omitempty"`
+	Confidence *float64          `json:"confidence,omitempty"`
+	Signals    []analyzer.Signal `json:"signals,omitempty"`
+}
+
+// sarifLevel maps a severity to a SARIF result level
+func sarifLevel(severity analyzer.Severity) string {
+	switch severity {
+	case analyzer.SeverityError:
+		return "error"
+	case analyzer.SeverityWarning:
+		return "warning"
+	case analyzer.SeverityInfo:
+		return "note"
+	}
+	return "none"
+}
+
+func newSARIFLog(results *analyzer.AnalysisResult, projectPath string) *sarifLog {
+	driver := sarifDriver{Name: "next-intl-analyzer", Version: analyzer.Version}
+	for _, rule := range analyzer.Rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.DefaultSeverity)},
+		})
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: make([]sarifResult, 0)}
+	for _, issue := range results.Issues() {
+		run.Results = append(run.Results, newSARIFResult(issue, results.Diff, projectPath))
+	}
+
+	// Fixed issues are reported as absent, as SARIF baselines do
+	if diff := results.Diff; diff != nil {
+		for _, issue := range diff.Fixed {
+			run.Results = append(run.Results, newSARIFResult(issue, diff, projectPath))
+		}
+		run.Properties = &sarifRunProperties{
+			Base:        diff.Base,
+			Head:        diff.Head,
+			AddedKeys:   append([]analyzer.KeyChange{}, diff.AddedKeys...),
+			RemovedKeys: append([]analyzer.KeyChange{}, diff.RemovedKeys...),
+			RenamedKeys: append([]analyzer.KeyRename{}, diff.RenamedKeys...),
+		}
+	}
+
+	return &sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}}
+}
+
+// sarifBaselineStates maps issue statuses to SARIF baseline states
+var sarifBaselineStates = map[analyzer.IssueStatus]string{
+	analyzer.StatusNew:       "new",
+	analyzer.StatusFixed:     "absent",
+	analyzer.StatusUnchanged: "unchanged",
+}
+
+func newSARIFResult(issue analyzer.Issue, diff *analyzer.RevisionDiff, projectPath string) sarifResult {
+	location := sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: projectRelativePath(projectPath, issue.File), URIBaseID: "%SRCROOT%"},
+	}
+	if issue.Line > 0 {
+		location.Region = &sarifRegion{StartLine: issue.Line}
+	}
+
+	result := sarifResult{
+		RuleID:              issue.Rule,
+		Level:               sarifLevel(issue.Severity),
+		Message:             sarifMessage{Text: issueMessage(issue)},
+		Locations:           []sarifLocation{{PhysicalLocation: location}},
+		PartialFingerprints: map[string]string{"nextIntlAnalyzer/v1": analyzer.Fingerprint(issue, projectPath)},
+	}
+	if diff != nil {
+		result.BaselineState = sarifBaselineStates[diff.Status(issue)]
+	}
+	if issue.Locale != "" {
+		result.Properties = &sarifProperties{Locale: issue.Locale}
+	}
+	if issue.Rule == analyzer.RuleHardcodedString {
+		confidence := issue.Confidence
+		result.Properties = &sarifProperties{Confidence: &confidence, Signals: issue.Signals}
+	}
+	return result
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+// The HTML report is a single file: styles and scripts are embedded in the
+// template, so that it can be opened offline or attached to a CI run.
+
+//go:embed report.html
+var htmlTemplateSource string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(part int, total int) int {
+		if total == 0 {
+			return 100
+		}
+		return part * 100 / total
+	},
+}).Parse(htmlTemplateSource))
+
+// snippetContext is the number of lines shown around a finding
+const snippetContext = 2
+
+type htmlReport struct {
+	Project   string
+	Generated string
+	Version   string
+
+	Summary     []htmlMetric
+	Sections    []htmlSection
+	Locales     []string
+	Directories []string
+	Severities  []analyzer.Severity
+	Coverage    []htmlCoverageRow
+	// Diff is set with --diff-base; issues then have a status, and Fixed
+	// lists the issues of the base revision that are gone
+	Diff  *analyzer.RevisionDiff
+	Fixed []htmlIssue
+}
+
+type htmlMetric struct {
+	Label string
+	Value int
+}
+
+// htmlSection lists the issues of one rule
+type htmlSection struct {
+	Rule        string
+	Description string
+	Issues      []htmlIssue
+}
+
+type htmlIssue struct {
+	// Anchor deep-links to the issue; it is its fingerprint, suffixed when
+	// several issues share a fingerprint
+	Anchor   string
+	Rule     string
+	Severity analyzer.Severity
+	Message  string
+	Key      string
+	File     string
+	Dir      string
+	Line     int
+	Locale   string
+	Status   analyzer.IssueStatus
+	Snippet  []htmlSnippetLine
+}
+
+// htmlSnippetLine is a line of source around a finding. On the line of the
+// finding, Match is the offending text.
+type htmlSnippetLine struct {
+	Number  int
+	Before  string
+	Match   string
+	After   string
+	Current bool
+}
+
+// htmlCoverageRow counts the keys of a namespace declared by every locale
+type htmlCoverageRow struct {
+	Namespace string
+	// All is set on the row counting the keys of every namespace
+	All      bool
+	Total    int
+	Declared []int
+}
+
+// writeHTMLReport writes results to w as a self-contained HTML page. Message
+// files and code snippets are read from the project.
+func writeHTMLReport(w io.Writer, results *analyzer.AnalysisResult, projectPath string, project *analyzer.Analyzer) error {
+	messages, err := project.Messages()
+	if err != nil {
+		return err
+	}
+
+	report := &htmlReport{
+		Project:   projectPath,
+		Generated: reportTime().Format("2006-01-02 15:04:05"),
+		Version:   analyzer.Version,
+		Summary: []htmlMetric{
+			{"Total translations", results.TotalTranslations},
+			{"Used translations", results.UsedTranslations},
+			{"Unused translations", len(results.UnusedTranslations)},
+			{"Undeclared translations", len(results.UndeclaredTranslations)},
+			{"Hardcoded strings", len(results.HardcodedStrings)},
+			{"Missing translations", len(results.MissingTranslations)},
+			{"Unused suppressions", len(results.UnusedSuppressions)},
+		},
+		Severities: []analyzer.Severity{analyzer.SeverityError, analyzer.SeverityWarning, analyzer.SeverityInfo},
+		Coverage:   coverageRows(messages),
+		Diff:       results.Diff,
+	}
+	for locale := range messages {
+		report.Locales = append(report.Locales, locale)
+	}
+	sort.Strings(report.Locales)
+
+	snippets := &snippetReader{fsys: project.FS(), projectPath: projectPath, files: make(map[string][]string)}
+	anchors := newFingerprints(projectPath)
+	directories := make(map[string]bool)
+	byRule := make(map[string][]htmlIssue)
+	newIssue := func(issue analyzer.Issue) htmlIssue {
+		file := projectRelativePath(projectPath, issue.File)
+		entry := htmlIssue{
+			Anchor:   anchors.next(issue),
+			Rule:     issue.Rule,
+			Severity: issue.Severity,
+			Message:  issueMessage(issue),
+			Key:      issue.Key,
+			File:     file,
+			Dir:      path.Dir(file),
+			Line:     issue.Line,
+			Locale:   issue.Locale,
+		}
+		if results.Diff != nil {
+			entry.Status = results.Diff.Status(issue)
+		}
+		return entry
+	}
+	for _, issue := range results.Issues() {
+		entry := newIssue(issue)
+		entry.Snippet = snippets.around(issue)
+		directories[entry.Dir] = true
+		byRule[issue.Rule] = append(byRule[issue.Rule], entry)
+	}
+	if results.Diff != nil {
+		// Fixed issues are located in the base revision, so they come
+		// without snippet
+		for _, issue := range results.Diff.Fixed {
+			report.Fixed = append(report.Fixed, newIssue(issue))
+		}
+	}
+	for dir := range directories {
+		report.Directories = append(report.Directories, dir)
+	}
+	sort.Strings(report.Directories)
+
+	for _, rule := range analyzer.Rules {
+		report.Sections = append(report.Sections, htmlSection{Rule: rule.ID, Description: rule.Description, Issues: byRule[rule.ID]})
+	}
+
+	return htmlTemplate.Execute(w, report)
+}
+
+// coverageRows counts by top-level namespace the keys that each locale
+// declares, out of the keys any locale declares
+func coverageRows(messages map[string]map[string]analyzer.Translation) []htmlCoverageRow {
+	var locales []string
+	for locale := range messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	column := make(map[string]int, len(locales))
+	for i, locale := range locales {
+		column[locale] = i
+	}
+
+	all := htmlCoverageRow{Namespace: "All keys", All: true, Declared: make([]int, len(locales))}
+	rows := make(map[string]*htmlCoverageRow)
+	for key, declaredIn := range analyzer.MessageKeys(messages) {
+		namespace := key
+		if i := strings.Index(key, "."); i > 0 {
+			namespace = key[:i]
+		}
+		row, ok := rows[namespace]
+		if !ok {
+			row = &htmlCoverageRow{Namespace: namespace, Declared: make([]int, len(locales))}
+			rows[namespace] = row
+		}
+		row.Total++
+		all.Total++
+		for _, locale := range declaredIn {
+			row.Declared[column[locale]]++
+			all.Declared[column[locale]]++
+		}
+	}
+
+	coverage := make([]htmlCoverageRow, 0, len(rows)+1)
+	for _, row := range rows {
+		coverage = append(coverage, *row)
+	}
+	sort.Slice(coverage, func(i, j int) bool { return coverage[i].Namespace < coverage[j].Namespace })
+	return append(coverage, all)
+}
+
+// snippetReader reads the lines around findings, keeping every file read
+type snippetReader struct {
+	fsys        fs.FS
+	projectPath string
+	files       map[string][]string
+}
+
+func (r *snippetReader) around(issue analyzer.Issue) []htmlSnippetLine {
+	if issue.Line <= 0 {
+		return nil
+	}
+	name := projectRelativePath(r.projectPath, issue.File)
+	lines, ok := r.files[name]
+	if !ok {
+		if content, err := fs.ReadFile(r.fsys, name); err == nil {
+			lines = strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+		}
+		r.files[name] = lines
+	}
+	if issue.Line > len(lines) {
+		return nil
+	}
+
+	// The offending text is the key or string itself, or the last segment of
+	// a key declared in a message file
+	needle := issue.Key
+	if issue.Rule == analyzer.RuleUnusedKey {
+		needle = fmt.Sprintf("%q", needle[strings.LastIndex(needle, ".")+1:])
+	}
+
+	var snippet []htmlSnippetLine
+	first := max(issue.Line-snippetContext, 1)
+	last := min(issue.Line+snippetContext, len(lines))
+	for number := first; number <= last; number++ {
+		line := htmlSnippetLine{Number: number, Before: lines[number-1]}
+		if number == issue.Line {
+			line.Current = true
+			if i := strings.Index(line.Before, needle); i >= 0 && needle != "" {
+				line.Before, line.Match, line.After = lines[number-1][:i], needle, lines[number-1][i+len(needle):]
+			}
+		}
+		snippet = append(snippet, line)
+	}
+	return snippet
+}
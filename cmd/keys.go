@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"next-intl-analyzer/pkg/analyzer"
+
+	"github.com/spf13/cobra"
+)
+
+// Files generate-keys writes inside the project by default, and the name of
+// the exported object
+const (
+	defaultKeysModule   = "message-keys.ts"
+	defaultKeysManifest = "message-keys.json"
+	defaultKeysObject   = "messageKeys"
+)
+
+var KeysCmd = &cobra.Command{
+	Use:   "generate-keys [project-path]",
+	Short: "Generate a module of message key constants and a key manifest",
+	Long: `Generate a JavaScript or TypeScript module exporting the keys of the reference
+locale as a nested object of constants, for code that does not use the React
+hooks, such as emails or PDFs:
+
+  createTranslator({ locale, messages })(messageKeys.Emails.welcome)
+
+The language follows the module extension: .ts and .mts write TypeScript,
+.cjs CommonJS, anything else an ES module.
+
+A JSON manifest of every key with the arguments of its message is written
+next to it. Set "keyManifest" in the configuration to the manifest path, and
+analyze counts keys referenced through the constants as used.
+
+With --check, nothing is written and the command fails when a file is
+missing or out of date.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := "."
+		if len(args) > 0 {
+			projectPath = args[0]
+		}
+		cmd.SilenceUsage = true
+
+		locale, _ := cmd.Flags().GetString("locale")
+		object, _ := cmd.Flags().GetString("object")
+		if !identifierPattern.MatchString(object) {
+			return fmt.Errorf("invalid --object %q: must be a JavaScript identifier", object)
+		}
+		modulePath, _ := cmd.Flags().GetString("module")
+		if modulePath == "" {
+			modulePath = filepath.Join(projectPath, defaultKeysModule)
+		}
+		manifestPath, _ := cmd.Flags().GetString("manifest")
+		if manifestPath == "" {
+			manifestPath = filepath.Join(projectPath, defaultKeysManifest)
+		}
+
+		reference, err := loadReferenceMessages(projectPath, locale)
+		if err != nil {
+			return err
+		}
+		manifest := newKeyManifest(reference, projectRelativePath(projectPath, modulePath), object)
+		manifestContent, err := manifest.Marshal()
+		if err != nil {
+			return err
+		}
+
+		check, _ := cmd.Flags().GetBool("check")
+		staleModule, err := writeGenerated(modulePath, generateKeysModule(reference, object, filepath.Ext(modulePath)), check)
+		if err != nil {
+			return err
+		}
+		staleManifest, err := writeGenerated(manifestPath, manifestContent, check)
+		if err != nil {
+			return err
+		}
+		if check {
+			return reportStale(staleModule || staleManifest, "generate-keys", modulePath, manifestPath)
+		}
+		fmt.Printf("📝 Constants of %d %s key(s) written to %s, manifest to %s\n", len(manifest.Keys), locale, modulePath, manifestPath)
+		return nil
+	},
+}
+
+func init() {
+	KeysCmd.Flags().String("locale", "en", "Reference locale whose messages define the keys")
+	KeysCmd.Flags().String("module", "", "Module to write; its extension picks the language (default: "+defaultKeysModule+" in the project root)")
+	KeysCmd.Flags().String("manifest", "", "Key manifest to write (default: "+defaultKeysManifest+" in the project root)")
+	KeysCmd.Flags().String("object", defaultKeysObject, "Name of the exported object of constants")
+	KeysCmd.Flags().Bool("check", false, "Fail instead of writing when a file is missing or out of date")
+}
+
+// newKeyManifest lists the keys of the reference messages in tree order.
+// module is the path of the generated module relative to the project.
+func newKeyManifest(reference *referenceMessages, module string, object string) *analyzer.KeyManifest {
+	manifest := analyzer.NewKeyManifest(reference.locale, module, object)
+	for _, key := range reference.keys() {
+		manifest.Keys = append(manifest.Keys, analyzer.ManifestKey{Key: key, Arguments: reference.arguments[key]})
+	}
+	return manifest
+}
+
+// generateKeysModule returns a module exporting the keys as object, in the
+// language of the module extension ext
+func generateKeysModule(reference *referenceMessages, object string, ext string) []byte {
+	typeScript := ext == ".ts" || ext == ".mts"
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by next-intl-analyzer generate-keys from %s.\n", strings.Join(reference.files, ", "))
+	b.WriteString("// Do not edit; run next-intl-analyzer generate-keys after changing messages.\n\n")
+
+	if ext == ".cjs" {
+		fmt.Fprintf(&b, "const %s = ", object)
+	} else {
+		fmt.Fprintf(&b, "export const %s = ", object)
+	}
+	writeKeyConstants(&b, reference, reference.root, 0)
+	if typeScript {
+		b.WriteString(" as const")
+	}
+	b.WriteString(";\n")
+
+	switch {
+	case ext == ".cjs":
+		fmt.Fprintf(&b, "\nmodule.exports = { %s };\n", object)
+	case typeScript:
+		b.WriteString("\nexport type MessageKey =")
+		keys := reference.keys()
+		for _, key := range keys {
+			fmt.Fprintf(&b, "\n  | %s", strconv.Quote(key))
+		}
+		if len(keys) == 0 {
+			b.WriteString(" never")
+		}
+		b.WriteString(";\n")
+	}
+	return []byte(b.String())
+}
+
+// writeKeyConstants writes a node of the message tree as an object literal
+// whose leaves are the full keys. Leaves document the arguments of their
+// message.
+func writeKeyConstants(b *strings.Builder, reference *referenceMessages, n *messageNode, depth int) {
+	if n.array || len(n.fields) == 0 && depth > 0 {
+		b.WriteString(strconv.Quote(n.key))
+		return
+	}
+
+	indent := strings.Repeat("  ", depth)
+	b.WriteString("{\n")
+	for _, name := range n.names() {
+		field := n.fields[name]
+		if args := reference.arguments[field.key]; len(args) > 0 {
+			signature := make([]string, 0, len(args))
+			for _, arg := range args {
+				signature = append(signature, fmt.Sprintf("%s: %s", arg.Name, arg.Type))
+			}
+			fmt.Fprintf(b, "%s  /** Arguments: %s */\n", indent, strings.Join(signature, ", "))
+		}
+		fmt.Fprintf(b, "%s  %s: ", indent, typeScriptProperty(name))
+		writeKeyConstants(b, reference, field, depth+1)
+		b.WriteString(",\n")
+	}
+	b.WriteString(indent + "}")
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"next-intl-analyzer/pkg/lsp"
+
+	"github.com/spf13/cobra"
+)
+
+var LspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a Language Server Protocol server over stdio",
+	Long: `Run a Language Server Protocol server that speaks JSON-RPC over stdin
+and stdout, for use by VS Code, Neovim and other LSP clients.
+
+The server provides:
+- Diagnostics for undeclared keys and hardcoded strings on open and change
+- Completion of message keys inside t('...'), scoped to the translator's namespace
+- Go-to-definition from a translation call to its declaration in messages/*.json
+- Hover text showing the message in every locale`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := lsp.NewServer(os.Stdin, os.Stdout).Run(cmd.Context())
+		if errors.Is(err, lsp.ErrExitWithoutShutdown) {
+			return &ExitError{Code: 1}
+		}
+		return err
+	},
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+// referenceMessages are the messages of the reference locale, which
+// generate-types and generate-keys derive their output from
+type referenceMessages struct {
+	locale string
+	// files are the message files of the locale, relative to the project
+	files []string
+	root  *messageNode
+	// arguments lists the ICU arguments of every message that has some
+	arguments map[string][]analyzer.MessageArgument
+}
+
+// loadReferenceMessages parses the message files of locale with
+// ParseTranslationFile and builds the tree of their keys
+func loadReferenceMessages(projectPath string, locale string) (*referenceMessages, error) {
+	project, err := analyzer.New(analyzer.WithDir(projectPath))
+	if err != nil {
+		return nil, err
+	}
+	localeFiles, err := project.TranslationFiles()
+	if err != nil {
+		return nil, err
+	}
+	files := localeFiles[locale]
+	if len(files) == 0 {
+		var locales []string
+		for known := range localeFiles {
+			locales = append(locales, known)
+		}
+		sort.Strings(locales)
+		return nil, fmt.Errorf("no message files for locale %q (found: %s)", locale, strings.Join(locales, ", "))
+	}
+	sort.Strings(files)
+
+	parser := analyzer.NewTranslationParser()
+	var declared []map[string]analyzer.Translation
+	for _, file := range files {
+		translations, err := parser.ParseTranslationFile(file)
+		if err != nil {
+			return nil, err
+		}
+		declared = append(declared, translations)
+	}
+	messages := parser.MergeTranslationMaps(declared...)
+
+	// Namespaces are the keys other keys are nested in; every other key is a
+	// message
+	namespaces := make(map[string]bool)
+	for key := range messages {
+		for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+			namespaces[key[:i]] = true
+		}
+	}
+
+	reference := &referenceMessages{locale: locale, root: newMessageNode(), arguments: make(map[string][]analyzer.MessageArgument)}
+	for _, file := range files {
+		reference.files = append(reference.files, projectRelativePath(projectPath, file))
+	}
+	for key, translation := range messages {
+		if namespaces[key] {
+			continue
+		}
+		// next-intl reads arrays with t.raw only, so they are not typed
+		// further than their top-level property
+		path := strings.Split(key, ".")
+		for i, segment := range path {
+			if j := strings.Index(segment, "["); j >= 0 {
+				path = append(path[:i], segment[:j])
+				array := reference.root.add(path)
+				array.array, array.key = true, strings.Join(path, ".")
+				break
+			}
+		}
+		node := reference.root.add(path)
+		if node.array {
+			continue
+		}
+		node.key = key
+
+		args, err := analyzer.MessageArguments(translation.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message %s in %s:%d: %w", key, projectRelativePath(projectPath, translation.File), translation.Line, err)
+		}
+		if len(args) > 0 {
+			reference.arguments[key] = args
+		}
+	}
+	return reference, nil
+}
+
+// keys returns the keys of the messages and arrays in tree order
+func (r *referenceMessages) keys() []string {
+	var keys []string
+	r.root.walk(func(node *messageNode) {
+		if node.key != "" {
+			keys = append(keys, node.key)
+		}
+	})
+	return keys
+}
+
+// messageNode is a namespace of the message tree, or a message when it has
+// no fields
+type messageNode struct {
+	fields map[string]*messageNode
+	// key is the full key of a message or array
+	key   string
+	array bool
+}
+
+func newMessageNode() *messageNode {
+	return &messageNode{fields: make(map[string]*messageNode)}
+}
+
+// add returns the node at path, creating the nodes on the way
+func (n *messageNode) add(path []string) *messageNode {
+	node := n
+	for _, segment := range path {
+		child, ok := node.fields[segment]
+		if !ok {
+			child = newMessageNode()
+			node.fields[segment] = child
+		}
+		node = child
+	}
+	return node
+}
+
+// names returns the names of the fields sorted
+func (n *messageNode) names() []string {
+	names := make([]string, 0, len(n.fields))
+	for name := range n.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// walk calls visit for n and every node below it, fields in sorted order.
+// The fields of arrays are not visited.
+func (n *messageNode) walk(visit func(node *messageNode)) {
+	visit(n)
+	if n.array {
+		return
+	}
+	for _, name := range n.names() {
+		n.fields[name].walk(visit)
+	}
+}
+
+// writeGenerated writes content to path, creating its directory. With check,
+// it only compares content with the file. It reports whether the file was
+// missing or out of date.
+func writeGenerated(path string, content []byte, check bool) (bool, error) {
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	stale := !bytes.Equal(current, content)
+	if check || !stale {
+		return stale, nil
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return stale, fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return stale, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return stale, nil
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"next-intl-analyzer/pkg/analyzer"
+
+	"github.com/spf13/cobra"
+)
+
+// formatMarkdown is the markdown report, which is only written with --output
+const formatMarkdown = "md"
+
+// stdoutPath is the --output path of stdout
+const stdoutPath = "-"
+
+// outputSpec is a report to write, given as <format>:<path> with --output
+type outputSpec struct {
+	format string
+	path   string
+}
+
+func (o outputSpec) toStdout() bool {
+	return o.path == stdoutPath
+}
+
+// parseOutputSpec parses a --output value. The path is everything after the
+// first colon, so that it may contain colons itself.
+func parseOutputSpec(spec string) (outputSpec, error) {
+	format, path, ok := strings.Cut(spec, ":")
+	if !ok || path == "" {
+		return outputSpec{}, fmt.Errorf("invalid --output %q (expected <format>:<path>, with - as path for stdout)", spec)
+	}
+	if format != formatMarkdown {
+		if err := validateFormat(format); err != nil || format == formatConsole || format == formatAuto {
+			return outputSpec{}, fmt.Errorf("unknown --output format %q (expected %s)", format, strings.Join(reportFormats(), ", "))
+		}
+	}
+	return outputSpec{format: format, path: path}, nil
+}
+
+// reportFormats lists the formats --output accepts
+func reportFormats() []string {
+	formats := []string{formatMarkdown}
+	for _, format := range outputFormats {
+		if format != formatConsole && format != formatAuto {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// outputSpecs returns the reports requested on the command line: the
+// --format output, the --output specs, then the deprecated --report flag. At
+// most one report may be written to stdout, where it replaces the console
+// output; without one, the console output goes there unless quiet is set.
+func outputSpecs(cmd *cobra.Command, format string, projectPath string, quiet bool) ([]outputSpec, error) {
+	var specs []outputSpec
+	if format != formatConsole {
+		specs = append(specs, outputSpec{format: format, path: stdoutPath})
+	}
+
+	values, _ := cmd.Flags().GetStringArray("output")
+	for _, value := range values {
+		spec, err := parseOutputSpec(value)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+
+	// Before --output, the markdown report always went to <project>/reports
+	if report, _ := cmd.Flags().GetBool("report"); report {
+		reportFile, _ := cmd.Flags().GetString("report-file")
+		specs = append(specs, outputSpec{format: formatMarkdown, path: filepath.Join(projectPath, "reports", reportFile)})
+	}
+
+	stdout := 0
+	for _, spec := range specs {
+		if spec.toStdout() {
+			stdout++
+		}
+	}
+	if stdout > 1 {
+		return nil, fmt.Errorf("only one output can be written to stdout")
+	}
+	if stdout == 0 && !quiet {
+		specs = append(specs, outputSpec{format: formatConsole, path: stdoutPath})
+	}
+	return specs, nil
+}
+
+// writeOutput writes results as requested by spec. The directory of a report
+// file is created when missing; nothing else is created.
+func writeOutput(spec outputSpec, results *analyzer.AnalysisResult, env reportEnv) error {
+	if spec.toStdout() {
+		return writeReport(os.Stdout, spec.format, results, env)
+	}
+
+	if dir := filepath.Dir(spec.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	file, err := os.Create(spec.path)
+	if err != nil {
+		return err
+	}
+	if err := writeReport(file, spec.format, results, env); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
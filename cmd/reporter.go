@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+// Reporters render analysis results, one per output format. The console
+// output is a reporter like the structured formats, so that the analyze
+// command only decides where results go and never how they look.
+
+// reportEnv is what reporters need besides the results: the project and the
+// analyzer that produced them, which the HTML report reads files from
+type reportEnv struct {
+	projectPath string
+	project     *analyzer.Analyzer
+}
+
+// reporter writes results to w in one format
+type reporter func(w io.Writer, results *analyzer.AnalysisResult, env reportEnv) error
+
+var reporters = map[string]reporter{
+	formatConsole: func(w io.Writer, results *analyzer.AnalysisResult, env reportEnv) error {
+		if results.Diff != nil {
+			displayRevisionDiff(w, results.Diff)
+		} else {
+			displayResults(w, results)
+		}
+		return nil
+	},
+	formatMarkdown: func(w io.Writer, results *analyzer.AnalysisResult, env reportEnv) error {
+		_, err := io.WriteString(w, markdownReport(results, env.projectPath))
+		return err
+	},
+	formatJSON: func(w io.Writer, results *analyzer.AnalysisResult, env reportEnv) error {
+		return writeJSON(w, newJSONReport(results, env.projectPath))
+	},
+	formatSARIF: func(w io.Writer, results *analyzer.AnalysisResult, env reportEnv) error {
+		return writeJSON(w, newSARIFLog(results, env.projectPath))
+	},
+	formatHTML: func(w io.Writer, results *analyzer.AnalysisResult, env reportEnv) error {
+		return writeHTMLReport(w, results, env.projectPath, env.project)
+	},
+	formatJUnit: func(w io.Writer, results *analyzer.AnalysisResult, env reportEnv) error {
+		return writeXML(w, newJUnitReport(results, env.projectPath))
+	},
+	formatCheckstyle: func(w io.Writer, results *analyzer.AnalysisResult, env reportEnv) error {
+		return writeXML(w, newCheckstyleReport(results, env.projectPath))
+	},
+	formatGitHub: func(w io.Writer, results *analyzer.AnalysisResult, env reportEnv) error {
+		return writeGitHubAnnotations(w, results)
+	},
+	formatGitLab: func(w io.Writer, results *analyzer.AnalysisResult, env reportEnv) error {
+		return writeJSON(w, newGitLabReport(results, env.projectPath))
+	},
+}
+
+// writeReport writes results to w in the given format
+func writeReport(w io.Writer, format string, results *analyzer.AnalysisResult, env reportEnv) error {
+	report, ok := reporters[format]
+	if !ok {
+		return fmt.Errorf("format %q cannot be written", format)
+	}
+	return report(w, results, env)
+}
+
+// writeJSON writes document to w as indented JSON
+func writeJSON(w io.Writer, document interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(document)
+}
+
+// ExitError ends a command with an exit status. The command has already
+// reported why, so it carries no message of its own.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
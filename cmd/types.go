@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"next-intl-analyzer/pkg/analyzer"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultTypesFile is where generate-types writes inside the project
+const defaultTypesFile = "next-intl.d.ts"
+
+var TypesCmd = &cobra.Command{
+	Use:   "generate-types [project-path]",
+	Short: "Generate TypeScript types of the messages",
+	Long: `Generate a TypeScript declaration file with the shape of the messages of the
+reference locale and the arguments of every ICU message. The file registers
+the messages with next-intl's AppConfig, so that keys and arguments are
+checked by the TypeScript compiler.
+
+With --check, nothing is written and the command fails when the file is
+missing or out of date, for instance in CI after messages changed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := "."
+		if len(args) > 0 {
+			projectPath = args[0]
+		}
+		cmd.SilenceUsage = true
+
+		locale, _ := cmd.Flags().GetString("locale")
+		outputPath, _ := cmd.Flags().GetString("output")
+		if outputPath == "" {
+			outputPath = filepath.Join(projectPath, defaultTypesFile)
+		}
+
+		reference, err := loadReferenceMessages(projectPath, locale)
+		if err != nil {
+			return err
+		}
+		check, _ := cmd.Flags().GetBool("check")
+		stale, err := writeGenerated(outputPath, generateTypes(reference), check)
+		if err != nil {
+			return err
+		}
+		if check {
+			return reportStale(stale, "generate-types", outputPath)
+		}
+		fmt.Printf("📝 Types of the %s messages written to %s\n", locale, outputPath)
+		return nil
+	},
+}
+
+// reportStale reports the result of --check, failing when a generated file
+// is stale
+func reportStale(stale bool, command string, paths ...string) error {
+	if stale {
+		fmt.Fprintf(os.Stderr, "❌ %s out of date; run next-intl-analyzer %s to update\n", strings.Join(paths, " or "), command)
+		return &ExitError{Code: 1}
+	}
+	fmt.Printf("✅ %s up to date\n", strings.Join(paths, " and "))
+	return nil
+}
+
+func init() {
+	TypesCmd.Flags().String("locale", "en", "Reference locale whose messages define the types")
+	TypesCmd.Flags().StringP("output", "o", "", "Declaration file to write (default: "+defaultTypesFile+" in the project root)")
+	TypesCmd.Flags().Bool("check", false, "Fail instead of writing when the declaration file is missing or out of date")
+}
+
+// generateTypes returns the declaration file of the reference messages. The
+// content only depends on the messages, so that --check can compare it byte
+// for byte.
+func generateTypes(reference *referenceMessages) []byte {
+	usesTags := false
+	for _, args := range reference.arguments {
+		for _, arg := range args {
+			usesTags = usesTags || arg.Type == analyzer.ArgumentTag
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by next-intl-analyzer generate-types from %s.\n", strings.Join(reference.files, ", "))
+	b.WriteString("// Do not edit; run next-intl-analyzer generate-types after changing messages.\n\n")
+	if usesTags {
+		b.WriteString("import type { ReactNode } from 'react';\n\n")
+	}
+
+	b.WriteString("export interface Messages ")
+	writeMessageType(&b, reference.root, 0)
+	b.WriteString("\n\n")
+
+	b.WriteString("// MessageArguments lists the arguments of every message that has some\n")
+	b.WriteString("export interface MessageArguments {")
+	keys := make([]string, 0, len(reference.arguments))
+	for key := range reference.arguments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\n  %s: {\n", strconv.Quote(key))
+		for _, arg := range reference.arguments[key] {
+			fmt.Fprintf(&b, "    %s: %s;\n", typeScriptProperty(arg.Name), typeScriptArgument(arg.Type))
+		}
+		b.WriteString("  };")
+	}
+	if len(keys) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("declare module 'next-intl' {\n  interface AppConfig {\n    Messages: Messages;\n  }\n}\n")
+	return []byte(b.String())
+}
+
+// writeMessageType writes the TypeScript type of a node of the message tree
+func writeMessageType(b *strings.Builder, n *messageNode, depth int) {
+	switch {
+	case n.array:
+		b.WriteString("unknown[]")
+		return
+	case len(n.fields) == 0 && depth > 0:
+		b.WriteString("string")
+		return
+	}
+
+	indent := strings.Repeat("  ", depth)
+	b.WriteString("{\n")
+	for _, name := range n.names() {
+		fmt.Fprintf(b, "%s  %s: ", indent, typeScriptProperty(name))
+		writeMessageType(b, n.fields[name], depth+1)
+		b.WriteString(";\n")
+	}
+	b.WriteString(indent + "}")
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeScriptProperty quotes property names that are not identifiers
+func typeScriptProperty(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// typeScriptArgument returns the TypeScript type of a message argument
+func typeScriptArgument(argumentType analyzer.ArgumentType) string {
+	switch argumentType {
+	case analyzer.ArgumentNumber:
+		return "number"
+	case analyzer.ArgumentDate:
+		return "Date"
+	case analyzer.ArgumentTag:
+		return "(chunks: ReactNode) => ReactNode"
+	}
+	return "string"
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+// fileStamp is the part of a file's metadata used to detect changes
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchProject polls the files read by projectAnalyzer, and the files listed
+// by watched such as the configuration, and re-runs analyze whenever one of
+// them is added, removed or modified. After each run it prints the issues
+// that appeared or were resolved since the previous run. Unchanged files are
+// served from the analyzer's parse cache; progress receives the end of every
+// run. It returns when ctx is cancelled.
+func watchProject(ctx context.Context, projectAnalyzer *analyzer.Analyzer, results *analyzer.AnalysisResult, interval time.Duration, progress io.Writer, watched func() []string, analyze func(context.Context) (*analyzer.AnalysisResult, error)) error {
+	stamps, err := snapshotFiles(projectAnalyzer, watched())
+	if err != nil {
+		return fmt.Errorf("failed to watch project: %w", err)
+	}
+	issues := results.Issues()
+
+	fmt.Println("👀 Watching for changes (press Ctrl-C to stop)...")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := snapshotFiles(projectAnalyzer, watched())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not scan project: %v\n", err)
+			continue
+		}
+		changed := changedFiles(stamps, current)
+		if len(changed) == 0 {
+			continue
+		}
+		stamps = current
+
+		fmt.Printf("\n🔁 [%s] %d file(s) changed:\n", time.Now().Format("15:04:05"), len(changed))
+		for _, file := range changed {
+			fmt.Printf("   ~ %s\n", file)
+		}
+
+		results, err := analyze(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Println()
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "\n⚠️  Analysis failed: %v\n", err)
+			continue
+		}
+		fmt.Fprintln(progress, "\r  ↳ Analysis complete!                      ")
+		displayDiagnostics(results.Diagnostics)
+
+		currentIssues := results.Issues()
+		added, resolved := analyzer.DiffIssues(issues, currentIssues)
+		issues = currentIssues
+
+		displayIssueDiff(added, resolved, results)
+	}
+}
+
+// snapshotFiles records the modification time and size of every file the
+// analyzer reads, and of the extra files that exist
+func snapshotFiles(projectAnalyzer *analyzer.Analyzer, extra []string) (map[string]fileStamp, error) {
+	files, err := projectAnalyzer.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	stamps := make(map[string]fileStamp, len(files)+len(extra))
+	for _, file := range append(files, extra...) {
+		info, err := os.Stat(file)
+		if err != nil {
+			// The file was removed between listing and stat, or an extra
+			// file does not exist yet
+			continue
+		}
+		stamps[file] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamps, nil
+}
+
+// changedFiles returns the sorted list of files added, removed or modified
+// between two snapshots
+func changedFiles(previous map[string]fileStamp, current map[string]fileStamp) []string {
+	var changed []string
+	for file, stamp := range current {
+		if old, ok := previous[file]; !ok || old != stamp {
+			changed = append(changed, file)
+		}
+	}
+	for file := range previous {
+		if _, ok := current[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func displayIssueDiff(added []analyzer.Issue, resolved []analyzer.Issue, results *analyzer.AnalysisResult) {
+	if len(added) == 0 && len(resolved) == 0 {
+		fmt.Println("   No new or resolved issues")
+	}
+
+	if len(added) > 0 {
+		fmt.Printf("   ➕ New issues (%d):\n", len(added))
+		for _, issue := range added {
+			fmt.Printf("      + %s\n", formatIssue(issue))
+		}
+	}
+
+	if len(resolved) > 0 {
+		fmt.Printf("   ✅ Resolved issues (%d):\n", len(resolved))
+		for _, issue := range resolved {
+			fmt.Printf("      - %s\n", formatIssue(issue))
+		}
+	}
+
+	fmt.Printf("   Now: %d unused, %d undeclared, %d hardcoded\n",
+		len(results.UnusedTranslations), len(results.UndeclaredTranslations), len(results.HardcodedStrings))
+}
+
+func formatIssue(issue analyzer.Issue) string {
+	location := issue.File
+	if issue.Line > 0 {
+		location = fmt.Sprintf("%s:%d", issue.File, issue.Line)
+	}
+	if issue.Locale != "" {
+		return fmt.Sprintf("[%s] %s (%s, locale: %s)%s", issue.Rule, issue.Key, location, issue.Locale, severityTag(issue.Severity))
+	}
+	return fmt.Sprintf("[%s] %s (%s)%s", issue.Rule, issue.Key, location, severityTag(issue.Severity))
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+// Output of workspaces, whose configuration defines several projects
+
+// projectCounts counts the issues of a project by rule
+func projectCounts(results *analyzer.AnalysisResult, project string) map[string]int {
+	counts := make(map[string]int)
+	for _, issue := range results.Workspace.Issues(results, project) {
+		counts[issue.Rule]++
+	}
+	return counts
+}
+
+// displayWorkspace writes the issues of every project and the shared keys
+// used by a single project to w
+func displayWorkspace(w io.Writer, results *analyzer.AnalysisResult) {
+	workspace := results.Workspace
+	if workspace == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "🏗️  Workspace projects (%d):\n", len(workspace.Projects))
+	for _, project := range workspace.Projects {
+		counts := projectCounts(results, project.Name)
+		fmt.Fprintf(w, "   📦 %s: %d unused, %d undeclared, %d hardcoded\n", project.Name,
+			counts[analyzer.RuleUnusedKey], counts[analyzer.RuleUndeclaredKey], counts[analyzer.RuleHardcodedString])
+	}
+	fmt.Fprintln(w)
+
+	if len(workspace.SharedKeys) > 0 {
+		fmt.Fprintf(w, "🔗 Shared keys used by a single project (%d):\n", len(workspace.SharedKeys))
+		for _, key := range workspace.SharedKeys {
+			fmt.Fprintf(w, "   - %s (in %s, used only by %s)\n", key.Key, key.Dir, key.UsedBy)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// markdownWorkspaceSection describes the projects of a workspace, if the
+// results are those of one
+func markdownWorkspaceSection(results *analyzer.AnalysisResult) string {
+	workspace := results.Workspace
+	if workspace == nil {
+		return ""
+	}
+
+	content := "## 🏗️ Workspace Projects\n\n"
+	content += "| Project | Sources | Messages | Unused | Undeclared | Hardcoded |\n"
+	content += "|---------|---------|----------|--------|------------|-----------|\n"
+	for _, project := range workspace.Projects {
+		counts := projectCounts(results, project.Name)
+		content += fmt.Sprintf("| %s | `%s` | `%s` | %d | %d | %d |\n", project.Name,
+			strings.Join(project.Sources, "`, `"), strings.Join(project.Messages, "`, `"),
+			counts[analyzer.RuleUnusedKey], counts[analyzer.RuleUndeclaredKey], counts[analyzer.RuleHardcodedString])
+	}
+	content += "\n"
+
+	if len(workspace.SharedKeys) > 0 {
+		content += "### Shared Keys Used by a Single Project\n\n"
+		content += "| Key | Messages | Used Only By |\n"
+		content += "|-----|----------|--------------|\n"
+		for _, key := range workspace.SharedKeys {
+			content += fmt.Sprintf("| `%s` | `%s` | %s |\n", key.Key, key.Dir, key.UsedBy)
+		}
+		content += "\n"
+	}
+	return content
+}
+
+// jsonWorkspace describes the projects of a workspace in the JSON report
+type jsonWorkspace struct {
+	Projects   []jsonProject   `json:"projects"`
+	SharedKeys []jsonSharedKey `json:"sharedKeys"`
+}
+
+type jsonProject struct {
+	Name     string         `json:"name"`
+	Sources  []string       `json:"sources"`
+	Messages []string       `json:"messages"`
+	Issues   map[string]int `json:"issues"`
+}
+
+type jsonSharedKey struct {
+	Key    string `json:"key"`
+	Dir    string `json:"dir"`
+	UsedBy string `json:"usedBy"`
+}
+
+func newJSONWorkspace(results *analyzer.AnalysisResult) *jsonWorkspace {
+	workspace := &jsonWorkspace{Projects: make([]jsonProject, 0), SharedKeys: make([]jsonSharedKey, 0)}
+	for _, project := range results.Workspace.Projects {
+		issues := make(map[string]int)
+		for _, rule := range analyzer.Rules {
+			issues[rule.ID] = 0
+		}
+		for rule, count := range projectCounts(results, project.Name) {
+			issues[rule] = count
+		}
+		workspace.Projects = append(workspace.Projects, jsonProject{Name: project.Name, Sources: project.Sources, Messages: project.Messages, Issues: issues})
+	}
+	for _, key := range results.Workspace.SharedKeys {
+		workspace.SharedKeys = append(workspace.SharedKeys, jsonSharedKey{Key: key.Key, Dir: key.Dir, UsedBy: key.UsedBy})
+	}
+	return workspace
+}
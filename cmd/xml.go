@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+// JUnit and Checkstyle XML group the issues by file, so that CI servers
+// show them next to test results and lint findings
+
+// writeXML writes document to w as an indented XML document
+func writeXML(w io.Writer, document interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// issuesByFile groups issues by their file relative to projectPath, keeping
+// the order of the issues of a file. Files are sorted.
+func issuesByFile(issues []analyzer.Issue, projectPath string) ([]string, map[string][]analyzer.Issue) {
+	byFile := make(map[string][]analyzer.Issue)
+	for _, issue := range issues {
+		file := projectRelativePath(projectPath, issue.File)
+		byFile[file] = append(byFile[file], issue)
+	}
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, byFile
+}
+
+// issueLocation returns the file and line of an issue
+func issueLocation(file string, issue analyzer.Issue) string {
+	if issue.Line > 0 {
+		return fmt.Sprintf("%s:%d", file, issue.Line)
+	}
+	return file
+}
+
+// JUnit XML, as read by Jenkins and GitLab: a test suite per file and a
+// failed test case per issue
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	File       string           `xml:"file,attr,omitempty"`
+	Line       int              `xml:"line,attr,omitempty"`
+	Properties *junitProperties `xml:"properties"`
+	Failure    *junitFailure    `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+func newJUnitReport(results *analyzer.AnalysisResult, projectPath string) *junitTestSuites {
+	report := &junitTestSuites{Name: "next-intl-analyzer"}
+	files, byFile := issuesByFile(results.Issues(), projectPath)
+	for _, file := range files {
+		suite := junitTestSuite{Name: file}
+		for _, issue := range byFile[file] {
+			name := fmt.Sprintf("%s: %s", issue.Rule, issue.Key)
+			if issue.Locale != "" {
+				name = fmt.Sprintf("%s (%s)", name, issue.Locale)
+			}
+			properties := []junitProperty{{Name: "rule", Value: issue.Rule}, {Name: "severity", Value: issue.Severity.String()}}
+			if issue.Locale != "" {
+				properties = append(properties, junitProperty{Name: "locale", Value: issue.Locale})
+			}
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:       name,
+				ClassName:  file,
+				File:       file,
+				Line:       issue.Line,
+				Properties: &junitProperties{Properties: properties},
+				Failure: &junitFailure{
+					Message: issueMessage(issue),
+					Type:    issue.Rule,
+					Text:    fmt.Sprintf("%s\nSeverity: %s\nLocation: %s", issueMessage(issue), issue.Severity, issueLocation(file, issue)),
+				},
+			})
+		}
+		suite.Tests, suite.Failures = len(suite.Cases), len(suite.Cases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+
+	// CI servers treat a report without tests as a missing report
+	if len(report.Suites) == 0 {
+		report.Suites = []junitTestSuite{{
+			Name:  "next-intl-analyzer",
+			Tests: 1,
+			Cases: []junitTestCase{{Name: "translations", ClassName: "next-intl-analyzer"}},
+		}}
+		report.Tests = 1
+	}
+	return report
+}
+
+// Checkstyle XML, as read by Sonar and most CI servers: an error element
+// per issue
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// checkstyleSeverity maps a severity to a Checkstyle severity
+func checkstyleSeverity(severity analyzer.Severity) string {
+	switch severity {
+	case analyzer.SeverityError:
+		return "error"
+	case analyzer.SeverityWarning:
+		return "warning"
+	case analyzer.SeverityInfo:
+		return "info"
+	}
+	return "ignore"
+}
+
+func newCheckstyleReport(results *analyzer.AnalysisResult, projectPath string) *checkstyleReport {
+	report := &checkstyleReport{Version: "4.3"}
+	files, byFile := issuesByFile(results.Issues(), projectPath)
+	for _, file := range files {
+		entry := checkstyleFile{Name: file}
+		for _, issue := range byFile[file] {
+			entry.Errors = append(entry.Errors, checkstyleError{
+				Line:     issue.Line,
+				Severity: checkstyleSeverity(issue.Severity),
+				Message:  issueMessage(issue),
+				Source:   "next-intl-analyzer." + issue.Rule,
+			})
+		}
+		report.Files = append(report.Files, entry)
+	}
+	return report
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Golden-file tests: every reporter runs over test-data and its output is
+// compared with the files in test-data/golden. Run with -update to rewrite
+// them after an intended change of the output, and review the diff:
+//
+//	go test -run TestGolden . -update
+var update = flag.Bool("update", false, "rewrite the golden files of test-data/golden")
+
+// goldenReport is an output of the analyze command, written to file
+type goldenReport struct {
+	file string
+	args []string
+}
+
+// reports are the outputs of every reporter; markdown is only written to a
+// file, named by a "{}" argument
+var reports = []goldenReport{
+	{"console.txt", nil},
+	{"report.json", []string{"--format", "json"}},
+	{"report.sarif", []string{"--format", "sarif"}},
+	{"report.html", []string{"--format", "html"}},
+	{"junit.xml", []string{"--format", "junit"}},
+	{"checkstyle.xml", []string{"--format", "checkstyle"}},
+	{"github.txt", []string{"--format", "github"}},
+	{"gitlab.json", []string{"--format", "gitlab"}},
+	{"report.md", []string{"--quiet", "--output", "md:{}"}},
+}
+
+func TestGolden(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "next-intl-analyzer")
+	if output, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, output)
+	}
+
+	tests := []struct {
+		name string
+		// dir is the project analyzed, golden the directory of its golden files
+		dir, golden string
+		args        []string
+	}{
+		// The workspace has a configuration of its own, and is analyzed alone
+		{"project", "test-data", "test-data/golden", []string{"--exclude", "workspace"}},
+		{"workspace", "test-data/workspace", "test-data/golden/workspace", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, report := range reports {
+				got := analyze(t, binary, tt.dir, append(tt.args[:len(tt.args):len(tt.args)], report.args...))
+				if report.file == "report.json" {
+					checkSummary(t, got)
+				}
+				name := filepath.Join(tt.golden, report.file)
+				if *update {
+					if err := os.MkdirAll(tt.golden, 0o755); err != nil {
+						t.Fatal(err)
+					}
+					if err := os.WriteFile(name, got, 0o644); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+				want, err := os.ReadFile(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Errorf("%s differs from the output; run go test -run TestGolden . -update if the change is intended\n%s", name, lineDiff(want, got))
+				}
+			}
+		})
+	}
+}
+
+// checkSummary checks that the counts of a JSON report add up, so that the
+// golden files cannot hold an impossible summary
+func checkSummary(t *testing.T, report []byte) {
+	t.Helper()
+	var decoded struct {
+		Summary struct {
+			Total  int `json:"totalTranslations"`
+			Used   int `json:"usedTranslations"`
+			Unused int `json:"unusedTranslations"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal(report, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	summary := decoded.Summary
+	if summary.Used+summary.Unused != summary.Total {
+		t.Errorf("%d used and %d unused translations of %d", summary.Used, summary.Unused, summary.Total)
+	}
+}
+
+// analyze runs the analyze command of binary over dir, from the root of the
+// repository, and returns what it writes to standard output, or to the file
+// of a "{}" argument
+func analyze(t *testing.T, binary string, dir string, args []string) []byte {
+	t.Helper()
+	output := filepath.Join(t.TempDir(), "report")
+	toFile := false
+	args = append([]string{"analyze", dir, "--no-cache", "--jobs", "1"}, args...)
+	for i, arg := range args {
+		if strings.Contains(arg, "{}") {
+			args[i] = strings.ReplaceAll(arg, "{}", output)
+			toFile = true
+		}
+	}
+
+	cmd := exec.Command(binary, args...)
+	// Reports carry their generation time
+	cmd.Env = append(os.Environ(), "SOURCE_DATE_EPOCH=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	// analyze exits with 1 because the projects have issues
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		t.Fatalf("analyze %v: %v\n%s", args, err, stderr.Bytes())
+	}
+	if !toFile {
+		return stdout.Bytes()
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+// lineDiff describes the first line where got differs from want
+func lineDiff(want []byte, got []byte) string {
+	wantLines, gotLines := strings.Split(string(want), "\n"), strings.Split(string(got), "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n- %s\n+ %s", i+1, w, g)
+		}
+	}
+	return ""
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 
 	"next-intl-analyzer/cmd"
 
@@ -18,13 +21,28 @@ in Next.js projects using next-intl for internationalization.
 Examples:
   next-intl-analyzer analyze ./my-nextjs-project
   next-intl-analyzer analyze /path/to/your/project`,
+	// main prints errors, and commands that end with an exit status have
+	// already reported why
+	SilenceErrors: true,
 }
 
 func main() {
 	rootCmd.AddCommand(cmd.AnalyzeCmd)
+	rootCmd.AddCommand(cmd.CacheCmd)
+	rootCmd.AddCommand(cmd.LspCmd)
+	rootCmd.AddCommand(cmd.TypesCmd)
+	rootCmd.AddCommand(cmd.KeysCmd)
 	
-	if err := rootCmd.Execute(); err != nil {
+	// Cancel the running analysis on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		var exitErr *cmd.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(2)
 	}
 } 
\ No newline at end of file
@@ -1,12 +1,22 @@
 package analyzer
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 )
 
+// Version is the analyzer release. It is part of every parse cache key, so
+// upgrading never reuses results produced by older heuristics.
+const Version = "0.3.0"
+
 // Translation represents a translation key and its usage
 type Translation struct {
 	Key      string
@@ -15,27 +25,71 @@ type Translation struct {
 	Used     bool
 	Declared bool
 	Locale   string
-	Type     string // "translation_call" or "hardcoded_string"
+	Type     TranslationType
+	Value    string // message of a declared key, empty for namespaces
+	Severity Severity
+	// Confidence and Signals explain why a hardcoded string was reported
+	Confidence float64
+	Signals    []Signal
 }
 
+// TranslationType tells where a Translation was found
+type TranslationType string
+
+const (
+	// TypeTranslationCall is a key passed to a translation function
+	TypeTranslationCall TranslationType = "translation_call"
+	// TypeHardcodedString is user-facing text written in a source file
+	TypeHardcodedString TranslationType = "hardcoded_string"
+	// TypeSuppression is a suppression comment; Key holds its text
+	TypeSuppression TranslationType = "suppression"
+)
+
 // AnalysisResult contains the results of the translation analysis
 type AnalysisResult struct {
-	UnusedTranslations    []Translation
+	UnusedTranslations     []Translation
 	UndeclaredTranslations []Translation
-	HardcodedStrings      []Translation
-	TotalTranslations     int
-	UsedTranslations      int
-	LocaleResults         map[string]*LocaleAnalysisResult
+	HardcodedStrings       []Translation
+	// MissingTranslations lists, for every locale lacking a key that other
+	// locales declare, the key at its declaration in the first such locale
+	MissingTranslations []Translation
+	// UnusedSuppressions lists suppression comments that silence no issue
+	UnusedSuppressions []Translation
+	TotalTranslations  int
+	UsedTranslations   int
+	LocaleResults      map[string]*LocaleAnalysisResult
+	// Baseline is set when known issues were filtered out with a baseline
+	Baseline *BaselineReport
+	// Diff is set when the results were compared with a base revision, see
+	// DiffResults
+	Diff *RevisionDiff
+	// Workspace is set when the configuration defines several projects, see
+	// Config.Projects
+	Workspace *WorkspaceReport
+	// Diagnostics lists the problems the analysis ran into, such as files
+	// that could not be read or parsed
+	Diagnostics []Diagnostic
 }
 
 // LocaleAnalysisResult contains analysis results for a specific locale
 type LocaleAnalysisResult struct {
-	Locale                string
-	UnusedTranslations    []Translation
+	Locale                 string
+	UnusedTranslations     []Translation
 	UndeclaredTranslations []Translation
-	HardcodedStrings      []Translation
-	TotalTranslations     int
-	UsedTranslations      int
+	HardcodedStrings       []Translation
+	TotalTranslations      int
+	UsedTranslations       int
+}
+
+// Locales returns the analyzed locales in order, so that reports do not
+// depend on map iteration
+func (r *AnalysisResult) Locales() []string {
+	locales := make([]string, 0, len(r.LocaleResults))
+	for locale := range r.LocaleResults {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
 }
 
 // ProgressCallback is a function that receives progress updates
@@ -43,21 +97,49 @@ type ProgressCallback func(stage string, progress int, total int)
 
 // Analyzer handles the analysis of next-intl translations
 type Analyzer struct {
-	projectPath      string
+	// projectPath prefixes the file paths of the results, see WithDir
+	projectPath string
+	fsys        fs.FS
+	files       []string
+	// sourceRoots and messageRoots limit the directories walked for files,
+	// see WithSourceRoots and WithMessageRoots
+	sourceRoots      []string
+	messageRoots     []string
 	results          *AnalysisResult
 	progressCallback ProgressCallback
+	concurrency      int
+	cache            *ParseCache
+	config           *Config
+	// keyReferences is set during Analyze when the configuration names a
+	// key manifest
+	keyReferences *keyReferences
+	// usage is set by Analyze to the keys the source files use, before
+	// rules, suppressions or a baseline hide any issue
+	usage *keyUsage
 }
 
+// NewAnalyzer returns an analyzer of the project directory at projectPath.
+// It is equivalent to New(WithDir(projectPath)).
 func NewAnalyzer(projectPath string) *Analyzer {
 	return &Analyzer{
-		projectPath: projectPath,
-		results: &AnalysisResult{
-			UnusedTranslations:    make([]Translation, 0),
-			UndeclaredTranslations: make([]Translation, 0),
-			HardcodedStrings:      make([]Translation, 0),
-			LocaleResults:         make(map[string]*LocaleAnalysisResult),
-		},
+		projectPath:      projectPath,
+		fsys:             DirFS(projectPath),
+		results:          newAnalysisResult(),
 		progressCallback: nil,
+		concurrency:      runtime.NumCPU(),
+		config:           DefaultConfig(),
+	}
+}
+
+func newAnalysisResult() *AnalysisResult {
+	return &AnalysisResult{
+		UnusedTranslations:     make([]Translation, 0),
+		UndeclaredTranslations: make([]Translation, 0),
+		HardcodedStrings:       make([]Translation, 0),
+		MissingTranslations:    make([]Translation, 0),
+		UnusedSuppressions:     make([]Translation, 0),
+		LocaleResults:          make(map[string]*LocaleAnalysisResult),
+		Diagnostics:            make([]Diagnostic, 0),
 	}
 }
 
@@ -66,76 +148,210 @@ func (a *Analyzer) SetProgressCallback(callback ProgressCallback) {
 	a.progressCallback = callback
 }
 
-func (a *Analyzer) Analyze() (*AnalysisResult, error) {
+// SetConcurrency sets the number of source files parsed in parallel.
+// Values below 1 fall back to the number of CPUs.
+func (a *Analyzer) SetConcurrency(workers int) {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	a.concurrency = workers
+}
+
+// SetCache sets the cache used to skip parsing unchanged files. A nil cache
+// disables caching.
+func (a *Analyzer) SetCache(cache *ParseCache) {
+	a.cache = cache
+}
+
+// SetConfig sets the configuration of rule severities. A nil config restores
+// the defaults.
+func (a *Analyzer) SetConfig(config *Config) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	a.config = config
+}
+
+// NewProjectCache returns a parse cache stored in DefaultCacheDir under
+// projectPath, keyed with the configuration of the parser used for config. A
+// nil config means the default configuration.
+func NewProjectCache(projectPath string, config *Config) *ParseCache {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return NewParseCache(filepath.Join(projectPath, DefaultCacheDir), NewTranslationParser(config.ParserOptions()...).ConfigHash())
+}
+
+// newParser returns a parser configured by the analyzer's configuration
+func (a *Analyzer) newParser() *TranslationParser {
+	return NewTranslationParser(a.config.ParserOptions()...)
+}
+
+func (a *Analyzer) reportProgress(stage string, progress int, total int) {
+	if a.progressCallback != nil {
+		a.progressCallback(stage, progress, total)
+	}
+}
+
+// Analyze runs the analysis. Every source file is parsed exactly once by a
+// bounded pool of workers; the returned results do not depend on the order in
+// which the workers finish. Cancelling ctx stops the analysis and returns
+// ctx.Err().
+func (a *Analyzer) Analyze(ctx context.Context) (*AnalysisResult, error) {
 	if err := a.validateProjectPath(); err != nil {
 		return nil, fmt.Errorf("invalid project path: %w", err)
 	}
 
-	// Find translation files with progress reporting
-	if a.progressCallback != nil {
-		a.progressCallback("Finding translation files", 0, 1)
+	if a.isWorkspace() {
+		return a.analyzeWorkspace(ctx)
 	}
+
+	a.results = newAnalysisResult()
+
+	a.reportProgress("Finding translation files", 0, 1)
 	translationFiles, err := a.findTranslationFiles()
 	if err != nil {
 		return nil, fmt.Errorf("error finding translation files: %w", err)
 	}
-	
-	// Find source files with progress reporting
-	if a.progressCallback != nil {
-		a.progressCallback("Finding source files", 0, 1)
-	}
+
+	a.reportProgress("Finding source files", 0, 1)
 	sourceFiles, err := a.findSourceFiles()
 	if err != nil {
 		return nil, fmt.Errorf("error finding source files: %w", err)
 	}
-	
-	if a.progressCallback != nil {
-		a.progressCallback("Grouping files by locale", 0, 1)
-	}
+
+	a.reportProgress("Grouping files by locale", 0, 1)
 	localeFiles := a.groupTranslationFilesByLocale(translationFiles)
 
-	// Analyze used translations with progress reporting
-	if a.progressCallback != nil {
-		a.progressCallback("Analyzing source files", 0, len(sourceFiles))
-	}
-	usedTranslations, err := a.analyzeUsedTranslations(sourceFiles)
+	a.keyReferences = a.loadKeyReferences()
+
+	a.reportProgress("Analyzing source files", 0, len(sourceFiles))
+	parsedFiles, err := a.parseSourceFiles(ctx, sourceFiles)
 	if err != nil {
-		return nil, fmt.Errorf("error analyzing used translations: %w", err)
+		return nil, err
 	}
+	a.usage = newKeyUsage(a.collectUsedTranslations(parsedFiles))
 
-	// Analyze each locale with progress reporting
-	localeCount := len(localeFiles)
-	localeIndex := 0
-	for locale, files := range localeFiles {
-		if a.progressCallback != nil {
-			a.progressCallback("Analyzing locale "+locale, localeIndex, localeCount)
-		}
-		localeResult, err := a.analyzeLocale(locale, files, usedTranslations)
-		if err != nil {
-			fmt.Printf("Warning: Error analyzing locale %s: %v\n", locale, err)
-			continue
-		}
-		a.results.LocaleResults[locale] = localeResult
-		localeIndex++
+	locales := make([]string, 0, len(localeFiles))
+	for locale := range localeFiles {
+		locales = append(locales, locale)
 	}
+	sort.Strings(locales)
 
-	// Generate overall results with progress reporting
-	if a.progressCallback != nil {
-		a.progressCallback("Generating results", 0, 1)
+	declared := make(map[string]map[string]Translation, len(locales))
+	for i, locale := range locales {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		a.reportProgress("Analyzing locale "+locale, i, len(locales))
+		declared[locale] = a.analyzeDeclaredTranslations(localeFiles[locale])
+		a.results.LocaleResults[locale] = a.analyzeLocale(locale, declared[locale], a.usage)
 	}
-	a.generateOverallResults()
-	
-	// Final progress update
-	if a.progressCallback != nil {
-		a.progressCallback("Complete", 1, 1)
+
+	a.reportProgress("Generating results", 0, 1)
+	if err := a.generateOverallResults(locales, collectHardcodedStrings(parsedFiles, a.config.ConfidenceThreshold())); err != nil {
+		return nil, fmt.Errorf("error generating results: %w", err)
 	}
+	a.results.MissingTranslations = collectMissingTranslations(declared)
+	applySuppressions(a.results, collectSuppressions(parsedFiles))
+	applyRules(a.results, a.config, a.projectPath)
+
+	a.reportProgress("Complete", 1, 1)
 
 	return a.results, nil
 }
 
+// Files returns every translation and source file the analysis reads, in
+// lexical order.
+func (a *Analyzer) Files() ([]string, error) {
+	if err := a.validateProjectPath(); err != nil {
+		return nil, fmt.Errorf("invalid project path: %w", err)
+	}
+
+	translationFiles, err := a.findTranslationFiles()
+	if err != nil {
+		return nil, fmt.Errorf("error finding translation files: %w", err)
+	}
+	sourceFiles, err := a.findSourceFiles()
+	if err != nil {
+		return nil, fmt.Errorf("error finding source files: %w", err)
+	}
+
+	files := append(translationFiles, sourceFiles...)
+	sort.Strings(files)
+	return files, nil
+}
+
+// FS returns the file system the project is read from. The files of the
+// results are named by joining the WithDir path and their name in it.
+func (a *Analyzer) FS() fs.FS {
+	return a.fsys
+}
+
+// TranslationFiles returns the message files of the project grouped by
+// locale.
+func (a *Analyzer) TranslationFiles() (map[string][]string, error) {
+	if err := a.validateProjectPath(); err != nil {
+		return nil, fmt.Errorf("invalid project path: %w", err)
+	}
+
+	translationFiles, err := a.findTranslationFiles()
+	if err != nil {
+		return nil, fmt.Errorf("error finding translation files: %w", err)
+	}
+	return a.groupTranslationFilesByLocale(translationFiles), nil
+}
+
+// SourceFiles returns the source files the analysis reads: the files of the
+// source roots, or of every workspace project, that are included and neither
+// excluded nor ignored.
+func (a *Analyzer) SourceFiles() ([]string, error) {
+	if err := a.validateProjectPath(); err != nil {
+		return nil, fmt.Errorf("invalid project path: %w", err)
+	}
+
+	sourceFiles, err := a.findSourceFiles()
+	if err != nil {
+		return nil, fmt.Errorf("error finding source files: %w", err)
+	}
+	return sourceFiles, nil
+}
+
+// Messages returns the keys declared in the message files of every locale.
+// Message files that cannot be parsed are skipped; Analyze reports them as
+// diagnostics.
+func (a *Analyzer) Messages() (map[string]map[string]Translation, error) {
+	localeFiles, err := a.TranslationFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	parser := a.newParser()
+	messages := make(map[string]map[string]Translation, len(localeFiles))
+	for locale, files := range localeFiles {
+		messages[locale] = make(map[string]Translation)
+		for _, file := range files {
+			declared, err := a.parseTranslationFile(parser, file)
+			if err != nil {
+				continue
+			}
+			for key, translation := range declared {
+				translation.Locale = locale
+				messages[locale][key] = translation
+			}
+		}
+	}
+	return messages, nil
+}
+
 func (a *Analyzer) validateProjectPath() error {
-	info, err := os.Stat(a.projectPath)
+	info, err := fs.Stat(a.fsys, ".")
 	if err != nil {
+		// Name the project rather than the root of its file system
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) && a.projectPath != "" {
+			pathErr.Path = a.projectPath
+		}
 		return err
 	}
 	if !info.IsDir() {
@@ -144,118 +360,362 @@ func (a *Analyzer) validateProjectPath() error {
 	return nil
 }
 
+// isTranslationFile reports whether name is a messages/<locale>.json file
+func isTranslationFile(name string) bool {
+	return path.Ext(name) == ".json" && path.Base(path.Dir(name)) == "messages"
+}
+
+// IsSourceFile reports whether name, a slash-separated or an operating
+// system path, is a JSX or TSX file, which the analysis parses for
+// translation calls and hardcoded strings
+func IsSourceFile(name string) bool {
+	ext := filepath.Ext(name)
+	return ext == ".jsx" || ext == ".tsx"
+}
+
+// isScriptFile reports whether name is a JavaScript or TypeScript file
+// without JSX, such as the code of emails or PDFs, which can only use
+// messages through key constants
+func isScriptFile(name string) bool {
+	switch path.Ext(name) {
+	case ".js", ".mjs", ".cjs", ".ts", ".mts":
+		return true
+	}
+	return false
+}
+
 func (a *Analyzer) findTranslationFiles() ([]string, error) {
-	var files []string
-	
-	err := filepath.Walk(a.projectPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		
-		// Skip node_modules directory
-		if info.IsDir() && info.Name() == "node_modules" {
-			return filepath.SkipDir
-		}
-		
-		// Only consider files in the messages directory with .json extension
-		if !info.IsDir() {
-			ext := filepath.Ext(path)
-			dir := filepath.Dir(path)
-			dirName := filepath.Base(dir)
-			
-			if ext == ".json" && dirName == "messages" {
-				files = append(files, path)
-			}
-		}
-		return nil
-	})
-	
-	return files, err
+	roots := a.roots(a.messageRoots, func(project ProjectConfig) []string { return project.Messages })
+	// Skip git, node_modules and the parse cache
+	return a.findFiles(roots, isTranslationFile, ".git", "node_modules", DefaultCacheDir)
 }
 
 func (a *Analyzer) findSourceFiles() ([]string, error) {
-	var files []string
-	
-	err := filepath.Walk(a.projectPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		
-		// Skip node_modules and .next directories
-		if info.IsDir() && (info.Name() == "node_modules" || info.Name() == ".next") {
-			return filepath.SkipDir
-		}
-		
-		if !info.IsDir() {
-			ext := filepath.Ext(path)
-			// Only include JSX and TSX files
-			if ext == ".jsx" || ext == ".tsx" {
-				files = append(files, path)
-			}
+	roots := a.roots(a.sourceRoots, func(project ProjectConfig) []string { return project.Sources })
+	// Scripts are only read for references through key constants
+	scripts := a.config.KeyManifest != ""
+	match := func(name string) bool {
+		return (IsSourceFile(name) || scripts && isScriptFile(name)) && a.config.included(name)
+	}
+	// Skip git, node_modules, .next and the parse cache
+	return a.findFiles(roots, match, ".git", "node_modules", ".next", DefaultCacheDir)
+}
+
+// roots returns the directories walked for files: the roots given as options,
+// else the directories of every workspace project. The whole project is
+// walked when there are none.
+func (a *Analyzer) roots(explicit []string, ofProject func(project ProjectConfig) []string) []string {
+	if explicit != nil {
+		return explicit
+	}
+	var roots []string
+	for _, project := range a.config.Projects {
+		roots = append(roots, cleanRoots(ofProject(project))...)
+	}
+	return roots
+}
+
+// inRoots reports whether name is one of roots or below one of them
+func inRoots(name string, roots []string) bool {
+	for _, root := range roots {
+		if root == "." || name == root || strings.HasPrefix(name, root+"/") {
+			return true
 		}
-		return nil
+	}
+	return false
+}
+
+// filePath returns the path reported in results for name, a path of the
+// analyzed file system
+func (a *Analyzer) filePath(name string) string {
+	if a.projectPath == "" {
+		return name
+	}
+	return filepath.Join(a.projectPath, filepath.FromSlash(name))
+}
+
+// fsName is the inverse of filePath
+func (a *Analyzer) fsName(file string) string {
+	if a.projectPath == "" {
+		return file
+	}
+	rel, err := filepath.Rel(a.projectPath, file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// diagnose records a problem the analysis ran into
+func (a *Analyzer) diagnose(severity Severity, file string, line int, format string, args ...interface{}) {
+	a.results.Diagnostics = append(a.results.Diagnostics, Diagnostic{
+		Severity: severity,
+		File:     file,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
 	})
-	
-	return files, err
 }
 
-func (a *Analyzer) analyzeDeclaredTranslations(files []string) (map[string]Translation, error) {
-	parser := NewTranslationParser()
+// analyzeDeclaredTranslations merges the keys of files. Files that cannot be
+// read or parsed are reported as diagnostics and skipped.
+func (a *Analyzer) analyzeDeclaredTranslations(files []string) map[string]Translation {
+	parser := a.newParser()
 	allDeclared := make(map[string]Translation)
-	
+
 	for _, file := range files {
-		declared, err := parser.ParseTranslationFile(file)
+		declared, err := a.parseTranslationFile(parser, file)
 		if err != nil {
-			fmt.Printf("Warning: Could not parse translation file %s: %v\n", file, err)
+			a.diagnose(SeverityWarning, file, 0, "Could not parse translation file %s: %v", file, err)
 			continue
 		}
-		
+
 		for key, translation := range declared {
 			allDeclared[key] = translation
 		}
 	}
-	
-	return allDeclared, nil
+
+	return allDeclared
 }
 
-func (a *Analyzer) analyzeUsedTranslations(files []string) (map[string]Translation, error) {
-	parser := NewTranslationParser()
-	allUsed := make(map[string]Translation)
-	
-	for i, file := range files {
-		// Report progress
-		if a.progressCallback != nil {
-			a.progressCallback("Analyzing source files", i+1, len(files))
+// parsedSourceFile holds the parse result of a single source file.
+type parsedSourceFile struct {
+	path string
+	*SourceFile
+	err error
+}
+
+// parseSourceFiles parses files concurrently using at most a.concurrency
+// workers. The returned slice is index-aligned with files.
+func (a *Analyzer) parseSourceFiles(ctx context.Context, files []string) ([]parsedSourceFile, error) {
+	parsed := make([]parsedSourceFile, len(files))
+	if len(files) == 0 {
+		return parsed, ctx.Err()
+	}
+
+	workers := a.concurrency
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	jobs := make(chan int)
+	done := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			parser := a.newParser()
+			for i := range jobs {
+				source, err := a.parseSourceFile(parser, files[i])
+				if source == nil {
+					source = &SourceFile{}
+				}
+				parsed[i] = parsedSourceFile{path: files[i], SourceFile: source, err: err}
+				done <- i
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range files {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
 		}
-		
-		used, err := parser.ParseSourceFile(file)
-		if err != nil {
-			fmt.Printf("Warning: Could not parse source file %s: %v\n", file, err)
-			continue
+	}()
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// Progress is reported from this goroutine only, so callbacks never run
+	// concurrently.
+	completed := 0
+	for range done {
+		completed++
+		a.reportProgress("Analyzing source files", completed, len(files))
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, file := range parsed {
+		if file.err != nil {
+			a.diagnose(SeverityWarning, file.path, 0, "Could not parse source file %s: %v", file.path, file.err)
 		}
-		
-		for key, translation := range used {
-			allUsed[key] = translation
+		for _, suppression := range append(file.Suppressions[:len(file.Suppressions):len(file.Suppressions)], file.UnknownDirectives...) {
+			if err := validateSuppression(suppression); err != nil {
+				a.diagnose(SeverityWarning, suppression.File, suppression.Line, "%v", err)
+			}
 		}
 	}
-	
-	return allUsed, nil
+
+	return parsed, nil
+}
+
+// parseSourceFile parses a single source file, reusing the cached result when
+// the file content has not changed since it was last parsed.
+func (a *Analyzer) parseSourceFile(parser *TranslationParser, file string) (*SourceFile, error) {
+	content, err := fs.ReadFile(a.fsys, a.fsName(file))
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %w", file, err)
+	}
+
+	var source *SourceFile
+	if !IsSourceFile(file) {
+		// Scripts have no JSX to parse, see isScriptFile
+		source = &SourceFile{Translations: make(map[string]Translation)}
+	} else if a.cache == nil {
+		source = parser.ParseSource(file, content)
+	} else {
+		key := a.cache.key(cacheKindSource, file, content)
+		if !a.cache.get(key, &source) {
+			source = parser.ParseSource(file, content)
+			a.cache.put(key, source)
+		}
+	}
+
+	// Key constants are matched after the cache, which does not depend on
+	// the manifest
+	if a.keyReferences != nil {
+		source = a.keyReferences.apply(file, content, source)
+	}
+	return source, nil
+}
+
+// loadKeyReferences reads the key manifest of the configuration. A manifest
+// that cannot be read is reported as a diagnostic, as keys referenced through
+// constants then show up as unused.
+func (a *Analyzer) loadKeyReferences() *keyReferences {
+	if a.config.KeyManifest == "" {
+		return nil
+	}
+	name := path.Clean(filepath.ToSlash(a.config.KeyManifest))
+	manifest, err := LoadKeyManifestFS(a.fsys, name)
+	if err != nil {
+		a.diagnose(SeverityWarning, a.filePath(name), 0, "Could not load key manifest: %v", err)
+		return nil
+	}
+	return manifest.references()
+}
+
+// parseTranslationFile is the message file counterpart of parseSourceFile.
+func (a *Analyzer) parseTranslationFile(parser *TranslationParser, file string) (map[string]Translation, error) {
+	content, err := fs.ReadFile(a.fsys, a.fsName(file))
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %w", file, err)
+	}
+
+	if a.cache == nil {
+		return parser.ParseTranslation(file, content)
+	}
+
+	key := a.cache.key(cacheKindTranslation, file, content)
+	var declared map[string]Translation
+	if a.cache.get(key, &declared) {
+		return declared, nil
+	}
+	declared, err = parser.ParseTranslation(file, content)
+	if err != nil {
+		return nil, err
+	}
+	a.cache.put(key, declared)
+	return declared, nil
+}
+
+// collectUsedTranslations merges the translation calls of the per-file parse
+// results in file order, so that a key used in several files always resolves
+// to the same location. Hardcoded strings are not keys, and are left out.
+func (a *Analyzer) collectUsedTranslations(files []parsedSourceFile) map[string]Translation {
+	allUsed := make(map[string]Translation)
+
+	for _, file := range files {
+		for key, translation := range file.Translations {
+			if translation.Type == TypeTranslationCall {
+				allUsed[key] = translation
+			}
+		}
+	}
+
+	return allUsed
+}
+
+// collectHardcodedStrings returns every hardcoded string found in files with
+// at least minConfidence, ordered by file, then line, then text.
+func collectHardcodedStrings(files []parsedSourceFile, minConfidence float64) []Translation {
+	hardcoded := make([]Translation, 0)
+	seen := make(map[string]bool)
+
+	for _, file := range files {
+		for _, translation := range file.Translations {
+			if translation.Type != TypeHardcodedString || translation.Confidence < minConfidence {
+				continue
+			}
+			id := fmt.Sprintf("%s\x00%s\x00%d", translation.Key, translation.File, translation.Line)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			hardcoded = append(hardcoded, translation)
+		}
+	}
+
+	sortTranslations(hardcoded)
+	return hardcoded
+}
+
+// collectSuppressions returns the suppression comments of files in file order
+func collectSuppressions(files []parsedSourceFile) []Suppression {
+	var suppressions []Suppression
+	for _, file := range files {
+		suppressions = append(suppressions, file.Suppressions...)
+	}
+	return suppressions
+}
+
+// sortTranslations orders translations by file, then line, then key.
+func sortTranslations(translations []Translation) {
+	sort.SliceStable(translations, func(i, j int) bool {
+		a, b := translations[i], translations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Key < b.Key
+	})
 }
 
 func (a *Analyzer) groupTranslationFilesByLocale(files []string) map[string][]string {
 	localeFiles := make(map[string][]string)
-	
+
 	for _, file := range files {
 		locale := a.extractLocaleFromPath(file)
 		if locale != "" {
 			localeFiles[locale] = append(localeFiles[locale], file)
 		}
 	}
-	
+
 	return localeFiles
 }
 
 func (a *Analyzer) extractLocaleFromPath(filePath string) string {
+	return LocaleFromPath(filePath)
+}
+
+// LocaleFromPath returns the locale of a messages/<locale>.json file, or an
+// empty string for any other path.
+func LocaleFromPath(filePath string) string {
 	fileName := filepath.Base(filePath)
 	ext := filepath.Ext(fileName)
 	// Only extract locale from JSON files in messages directory
@@ -267,113 +727,123 @@ func (a *Analyzer) extractLocaleFromPath(filePath string) string {
 			return locale
 		}
 	}
-	
+
 	return ""
 }
 
-func (a *Analyzer) analyzeLocale(locale string, files []string, usedTranslations map[string]Translation) (*LocaleAnalysisResult, error) {
-	declaredTranslations, err := a.analyzeDeclaredTranslations(files)
-	if err != nil {
-		return nil, fmt.Errorf("error analyzing declared translations for locale %s: %w", locale, err)
-	}
-
+// analyzeLocale compares the keys a locale declares with the keys used.
+// declaredTranslations is updated with the locale.
+func (a *Analyzer) analyzeLocale(locale string, declaredTranslations map[string]Translation, usage *keyUsage) *LocaleAnalysisResult {
 	for key, translation := range declaredTranslations {
 		translation.Locale = locale
 		declaredTranslations[key] = translation
 	}
 
 	localeResult := &LocaleAnalysisResult{
-		Locale:                locale,
-		UnusedTranslations:    make([]Translation, 0),
+		Locale:                 locale,
+		UnusedTranslations:     make([]Translation, 0),
 		UndeclaredTranslations: make([]Translation, 0),
-		HardcodedStrings:      make([]Translation, 0), // This will remain empty as we'll handle hardcoded strings globally
-	}
-	
-	// Build a map of parent keys that have used child keys
-	usedParentKeys := make(map[string]bool)
-	for key := range usedTranslations {
-		// For each used key, mark all parent namespaces as "used"
-		parts := strings.Split(key, ".")
-		for i := 1; i < len(parts); i++ {
-			parentKey := strings.Join(parts[:i], ".")
-			usedParentKeys[parentKey] = true
-		}
+		HardcodedStrings:       make([]Translation, 0), // This will remain empty as we'll handle hardcoded strings globally
 	}
 
 	for key, translation := range declaredTranslations {
-		// Check if the key is directly used or if it's a parent namespace of a used key
-		isUsed := false
-		if _, exists := usedTranslations[key]; exists {
-			isUsed = true
-		} else if usedParentKeys[key] {
-			// This is a parent namespace of a used key
-			isUsed = true
-		}
-		
-		if !isUsed {
+		if usage.uses(key) {
+			localeResult.UsedTranslations++
+		} else {
 			localeResult.UnusedTranslations = append(localeResult.UnusedTranslations, translation)
 		}
 	}
 
-	for key, translation := range usedTranslations {
-		// Only process translation calls, not hardcoded strings
-		if translation.Type == "translation_call" {
-			if _, exists := declaredTranslations[key]; !exists {
-				translation.Locale = locale
-				translation.Declared = false
-				localeResult.UndeclaredTranslations = append(localeResult.UndeclaredTranslations, translation)
-			}
+	// Hardcoded strings are handled separately in generateOverallResults
+	for key, translation := range usage.used {
+		if _, exists := declaredTranslations[key]; !exists {
+			translation.Locale = locale
+			translation.Declared = false
+			localeResult.UndeclaredTranslations = append(localeResult.UndeclaredTranslations, translation)
 		}
-		// Hardcoded strings are now handled separately in generateOverallResults
 	}
 
+	sortTranslations(localeResult.UnusedTranslations)
+	sortTranslations(localeResult.UndeclaredTranslations)
+
 	localeResult.TotalTranslations = len(declaredTranslations)
-	localeResult.UsedTranslations = len(usedTranslations)
 
-	return localeResult, nil
+	return localeResult
 }
 
-func (a *Analyzer) generateOverallResults() {
-	allUnused := make([]Translation, 0)
-	allUndeclared := make([]Translation, 0)
-	allHardcoded := make([]Translation, 0)
-	totalTranslations := 0
-	usedTranslations := 0
-	
-	// Process hardcoded strings globally, not per locale
-	// Find all hardcoded strings from the source files
-	sourceFiles, _ := a.findSourceFiles()
-	parser := NewTranslationParser()
-	
-	for i, file := range sourceFiles {
-		// Report progress
-		if a.progressCallback != nil {
-			a.progressCallback("Finding hardcoded strings", i+1, len(sourceFiles))
-		}
-		
-		used, err := parser.ParseSourceFile(file)
-		if err != nil {
+// collectMissingTranslations returns a translation for every locale of
+// declared that lacks a key declared by other locales, located at the
+// declaration of the key in the first locale declaring it
+func collectMissingTranslations(declared map[string]map[string]Translation) []Translation {
+	missing := make([]Translation, 0)
+	locales := make([]string, 0, len(declared))
+	for locale := range declared {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	for key, declaring := range MessageKeys(declared) {
+		if len(declaring) == len(locales) {
 			continue
 		}
-		
-		for _, translation := range used {
-			if translation.Type == "hardcoded_string" {
-				// Don't add duplicates
-				isDuplicate := false
-				for _, existing := range allHardcoded {
-					if existing.Key == translation.Key && existing.File == translation.File && existing.Line == translation.Line {
-						isDuplicate = true
-						break
-					}
-				}
-				if !isDuplicate {
-					allHardcoded = append(allHardcoded, translation)
-				}
+		present := make(map[string]bool, len(declaring))
+		for _, locale := range declaring {
+			present[locale] = true
+		}
+		for _, locale := range locales {
+			if !present[locale] {
+				translation := declared[declaring[0]][key]
+				translation.Locale = locale
+				missing = append(missing, translation)
 			}
 		}
 	}
 
-	for _, localeResult := range a.results.LocaleResults {
+	// Locales are already in order for each key
+	sortTranslations(missing)
+	return missing
+}
+
+// keyUsage tells which declared keys the source files use: the keys used
+// directly and the namespaces of used keys
+type keyUsage struct {
+	used map[string]Translation
+	// parents holds every namespace of a used key
+	parents map[string]bool
+}
+
+func newKeyUsage(usedTranslations map[string]Translation) *keyUsage {
+	usage := &keyUsage{used: usedTranslations, parents: make(map[string]bool)}
+	for key := range usedTranslations {
+		parts := strings.Split(key, ".")
+		for i := 1; i < len(parts); i++ {
+			usage.parents[strings.Join(parts[:i], ".")] = true
+		}
+	}
+	return usage
+}
+
+// uses reports whether key is used directly or is a namespace of a used key
+func (u *keyUsage) uses(key string) bool {
+	_, used := u.used[key]
+	return used || u.parents[key]
+}
+
+// generateOverallResults merges the results of every locale. Each locale is
+// analyzed before, so a missing locale result is an error.
+func (a *Analyzer) generateOverallResults(locales []string, hardcoded []Translation) error {
+	allUnused := make([]Translation, 0)
+	allUndeclared := make([]Translation, 0)
+	totalTranslations := 0
+	usedTranslations := 0
+
+	// Hardcoded strings are collected globally, not per locale, from the
+	// same parse results that produced the used translations.
+	for _, locale := range locales {
+		localeResult, ok := a.results.LocaleResults[locale]
+		if !ok {
+			return fmt.Errorf("locale %s was not analyzed", locale)
+		}
 		allUnused = append(allUnused, localeResult.UnusedTranslations...)
 		allUndeclared = append(allUndeclared, localeResult.UndeclaredTranslations...)
 		totalTranslations += localeResult.TotalTranslations
@@ -382,7 +852,8 @@ func (a *Analyzer) generateOverallResults() {
 
 	a.results.UnusedTranslations = allUnused
 	a.results.UndeclaredTranslations = allUndeclared
-	a.results.HardcodedStrings = allHardcoded
+	a.results.HardcodedStrings = hardcoded
 	a.results.TotalTranslations = totalTranslations
 	a.results.UsedTranslations = usedTranslations
-} 
\ No newline at end of file
+	return nil
+}
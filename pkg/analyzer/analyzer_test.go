@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestUsedTranslations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"messages/en.json": {Data: []byte(`{"Common":{"save":"Save","cancel":"Cancel"},"Other":{"title":"Title"}}`)},
+		"messages/de.json": {Data: []byte(`{"Common":{"save":"Speichern"}}`)},
+		"src/page.tsx": {Data: []byte(`import { useTranslations } from 'next-intl';
+
+export default function Page() {
+  const t = useTranslations('Common');
+  return <div><button>{t('save')}</button><button>{t('missing')}</button><p>Welcome to our application</p></div>;
+}
+`)},
+	}
+	a, err := New(WithFS(fsys), WithConfig(DefaultConfig()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := a.Analyze(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.HardcodedStrings) != 1 {
+		t.Errorf("hardcoded strings = %v, want one", results.HardcodedStrings)
+	}
+
+	// Used keys are declared keys, and their namespaces; undeclared keys and
+	// hardcoded strings are not counted
+	tests := []struct {
+		locale      string
+		total, used int
+	}{
+		{"en", 5, 2},
+		{"de", 2, 2},
+	}
+	for _, tt := range tests {
+		localeResult := results.LocaleResults[tt.locale]
+		if localeResult.TotalTranslations != tt.total || localeResult.UsedTranslations != tt.used {
+			t.Errorf("%s: %d used of %d, want %d of %d", tt.locale,
+				localeResult.UsedTranslations, localeResult.TotalTranslations, tt.used, tt.total)
+		}
+	}
+	if results.TotalTranslations != 7 || results.UsedTranslations != 4 {
+		t.Errorf("%d used of %d, want 4 of 7", results.UsedTranslations, results.TotalTranslations)
+	}
+}
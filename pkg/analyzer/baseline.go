@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// baselineVersion is the format version written to baseline files
+const baselineVersion = 1
+
+// Baseline is a set of known issues that should not fail the analysis.
+// Issues are matched by fingerprint, so editing unrelated lines of a file
+// does not invalidate the baseline.
+type Baseline struct {
+	Version int             `json:"version"`
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// BaselineEntry records a known issue. Count is the number of identical
+// issues, for example the same hardcoded string repeated in one file.
+type BaselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	Rule        string `json:"rule"`
+	Key         string `json:"key"`
+	File        string `json:"file"`
+	Locale      string `json:"locale,omitempty"`
+	Count       int    `json:"count"`
+}
+
+// BaselineReport describes how a baseline was applied to the results
+type BaselineReport struct {
+	// Suppressed is the number of issues matched by the baseline
+	Suppressed int
+	// Fixed lists baseline entries that no longer match an issue. Count is
+	// the number of occurrences that are gone.
+	Fixed []BaselineEntry
+}
+
+// Fingerprint identifies an issue by rule, locale, file (relative to
+// projectPath) and whitespace-normalized key or text. The line number is
+// deliberately left out.
+func Fingerprint(issue Issue, projectPath string) string {
+	h := sha256.New()
+	for _, part := range []string{issue.Rule, issue.Locale, relativePath(projectPath, issue.File), normalizeText(issue.Key)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// relativePath returns file relative to root using forward slashes, or file
+// itself when it is not inside root.
+func relativePath(root string, file string) string {
+	rel, err := filepath.Rel(root, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// NewBaseline returns a baseline containing every issue of results
+func NewBaseline(results *AnalysisResult, projectPath string) *Baseline {
+	entries := make(map[string]*BaselineEntry)
+	for _, issue := range results.Issues() {
+		fingerprint := Fingerprint(issue, projectPath)
+		if entry, ok := entries[fingerprint]; ok {
+			entry.Count++
+			continue
+		}
+		entries[fingerprint] = &BaselineEntry{
+			Fingerprint: fingerprint,
+			Rule:        issue.Rule,
+			Key:         normalizeText(issue.Key),
+			File:        relativePath(projectPath, issue.File),
+			Locale:      issue.Locale,
+			Count:       1,
+		}
+	}
+
+	baseline := &Baseline{Version: baselineVersion, Entries: make([]BaselineEntry, 0, len(entries))}
+	for _, entry := range entries {
+		baseline.Entries = append(baseline.Entries, *entry)
+	}
+	baseline.sort()
+	return baseline
+}
+
+// LoadBaseline reads a baseline file written by Save
+func LoadBaseline(path string) (*Baseline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading baseline %s: %w", path, err)
+	}
+
+	var baseline Baseline
+	if err := json.Unmarshal(content, &baseline); err != nil {
+		return nil, fmt.Errorf("error parsing baseline %s: %w", path, err)
+	}
+	if baseline.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s", baseline.Version, path)
+	}
+	// Fixed entries are reported in entry order, which must not depend on
+	// edits of the file
+	baseline.sort()
+	return &baseline, nil
+}
+
+// Save writes the baseline to path as indented JSON, with entries sorted so
+// that the file diffs cleanly under version control.
+func (b *Baseline) Save(path string) error {
+	b.sort()
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create baseline directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline %s: %w", path, err)
+	}
+	return nil
+}
+
+// Apply removes the issues matched by the baseline from results and reports
+// which baseline entries are fixed. Results are modified in place.
+func (b *Baseline) Apply(results *AnalysisResult, projectPath string) *BaselineReport {
+	remaining := make(map[string]int, len(b.Entries))
+	for _, entry := range b.Entries {
+		remaining[entry.Fingerprint] += entry.Count
+	}
+
+	report := &BaselineReport{}
+	known := func(rule string) func(Translation) bool {
+		return func(translation Translation) bool {
+			fingerprint := Fingerprint(Issue{Rule: rule, Translation: translation}, projectPath)
+			if remaining[fingerprint] > 0 {
+				remaining[fingerprint]--
+				report.Suppressed++
+				return true
+			}
+			return false
+		}
+	}
+
+	results.UnusedTranslations = removeTranslations(results.UnusedTranslations, known(RuleUnusedKey))
+	results.UndeclaredTranslations = removeTranslations(results.UndeclaredTranslations, known(RuleUndeclaredKey))
+	results.HardcodedStrings = removeTranslations(results.HardcodedStrings, known(RuleHardcodedString))
+	results.MissingTranslations = removeTranslations(results.MissingTranslations, known(RuleMissingTranslation))
+	results.UnusedSuppressions = removeTranslations(results.UnusedSuppressions, known(RuleUnusedSuppression))
+
+	// Keep the per-locale lists consistent with the overall lists
+	for locale, localeResult := range results.LocaleResults {
+		inLocale := func(translation Translation) bool { return translation.Locale != locale }
+		localeResult.UnusedTranslations = removeTranslations(results.UnusedTranslations, inLocale)
+		localeResult.UndeclaredTranslations = removeTranslations(results.UndeclaredTranslations, inLocale)
+	}
+
+	for _, entry := range b.Entries {
+		if count := remaining[entry.Fingerprint]; count > 0 {
+			entry.Count = count
+			report.Fixed = append(report.Fixed, entry)
+			remaining[entry.Fingerprint] = 0
+		}
+	}
+	return report
+}
+
+// Prune returns a copy of the baseline without the occurrences that are
+// fixed according to report. It never adds entries, so new issues stay
+// visible.
+func (b *Baseline) Prune(report *BaselineReport) *Baseline {
+	fixed := make(map[string]int, len(report.Fixed))
+	for _, entry := range report.Fixed {
+		fixed[entry.Fingerprint] += entry.Count
+	}
+
+	pruned := &Baseline{Version: baselineVersion, Entries: make([]BaselineEntry, 0, len(b.Entries))}
+	for _, entry := range b.Entries {
+		removed := fixed[entry.Fingerprint]
+		if removed > entry.Count {
+			removed = entry.Count
+		}
+		fixed[entry.Fingerprint] -= removed
+		entry.Count -= removed
+		if entry.Count > 0 {
+			pruned.Entries = append(pruned.Entries, entry)
+		}
+	}
+	return pruned
+}
+
+func (b *Baseline) sort() {
+	sort.Slice(b.Entries, func(i, j int) bool {
+		x, y := b.Entries[i], b.Entries[j]
+		if x.Rule != y.Rule {
+			return x.Rule < y.Rule
+		}
+		if x.Locale != y.Locale {
+			return x.Locale < y.Locale
+		}
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		return x.Key < y.Key
+	})
+}
+
+// removeTranslations returns the translations for which remove is false
+func removeTranslations(translations []Translation, remove func(Translation) bool) []Translation {
+	kept := make([]Translation, 0, len(translations))
+	for _, translation := range translations {
+		if !remove(translation) {
+			kept = append(kept, translation)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// baselineResults returns results with the issues given as "rule locale file
+// line key", with files below the project directory
+func baselineResults(issues ...string) *AnalysisResult {
+	results := newAnalysisResult()
+	for _, issue := range issues {
+		var rule, locale, file, key string
+		var line int
+		if _, err := fmt.Sscanf(issue, "%s %s %s %d %q", &rule, &locale, &file, &line, &key); err != nil {
+			panic(fmt.Sprintf("%s: %v", issue, err))
+		}
+		if locale == "-" {
+			locale = ""
+		}
+		translation := Translation{Key: key, Locale: locale, File: filepath.Join("project", file), Line: line, Severity: SeverityError}
+		switch rule {
+		case RuleUnusedKey:
+			results.UnusedTranslations = append(results.UnusedTranslations, translation)
+		case RuleUndeclaredKey:
+			results.UndeclaredTranslations = append(results.UndeclaredTranslations, translation)
+		case RuleHardcodedString:
+			results.HardcodedStrings = append(results.HardcodedStrings, translation)
+		}
+	}
+	return results
+}
+
+func TestBaseline(t *testing.T) {
+	known := baselineResults(
+		`unused-key en messages/en.json 3 "Common.cancel"`,
+		`undeclared-key de src/page.tsx 4 "Common.missing"`,
+		`hardcoded-string - src/page.tsx 8 "Welcome to our app"`,
+		`hardcoded-string - src/page.tsx 12 "Welcome to our app"`,
+	)
+	file := filepath.Join(t.TempDir(), "config", "baseline.json")
+	if err := NewBaseline(known, "project").Save(file); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := LoadBaseline(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(baseline.Entries) != 3 {
+		t.Fatalf("entries = %+v, want 3 with a count of 2", baseline.Entries)
+	}
+
+	t.Run("known issues", func(t *testing.T) {
+		// Lines moved and whitespace changed
+		results := baselineResults(
+			`unused-key en messages/en.json 5 "Common.cancel"`,
+			`undeclared-key de src/page.tsx 6 "Common.missing"`,
+			`hardcoded-string - src/page.tsx 10 "Welcome  to our app"`,
+			`hardcoded-string - src/page.tsx 20 "Welcome to our app"`,
+		)
+		report := baseline.Apply(results, "project")
+		if report.Suppressed != 4 || len(report.Fixed) != 0 || results.HasIssues() {
+			t.Errorf("suppressed %d, fixed %+v, left %+v; want every issue suppressed", report.Suppressed, report.Fixed, results.Issues())
+		}
+	})
+
+	t.Run("new issues", func(t *testing.T) {
+		results := baselineResults(
+			`unused-key en messages/en.json 3 "Common.cancel"`,
+			`unused-key de messages/de.json 3 "Common.cancel"`,
+			`undeclared-key de src/page.tsx 4 "Common.missing"`,
+			`hardcoded-string - src/page.tsx 8 "Welcome to our app"`,
+			`hardcoded-string - src/page.tsx 12 "Welcome to our app"`,
+			`hardcoded-string - src/page.tsx 16 "Welcome to our app"`,
+		)
+		baseline.Apply(results, "project")
+		// Another locale, and one occurrence more than known
+		var left []string
+		for _, issue := range results.Issues() {
+			left = append(left, issue.Rule+" "+issue.Locale+" "+issue.Key)
+		}
+		want := []string{"hardcoded-string  Welcome to our app", "unused-key de Common.cancel"}
+		if !reflect.DeepEqual(left, want) {
+			t.Errorf("issues = %q, want %q", left, want)
+		}
+		if !results.Fails(SeverityError) {
+			t.Error("new issues do not fail the analysis")
+		}
+	})
+
+	t.Run("fixed issues", func(t *testing.T) {
+		results := baselineResults(
+			`undeclared-key de src/page.tsx 4 "Common.missing"`,
+			`hardcoded-string - src/page.tsx 8 "Welcome to our app"`,
+		)
+		report := baseline.Apply(results, "project")
+		var fixed []string
+		for _, entry := range report.Fixed {
+			fixed = append(fixed, fmt.Sprintf("%s %s %d", entry.Rule, entry.Key, entry.Count))
+		}
+		want := []string{"hardcoded-string Welcome to our app 1", "unused-key Common.cancel 1"}
+		if !reflect.DeepEqual(fixed, want) {
+			t.Errorf("fixed = %q, want %q", fixed, want)
+		}
+
+		// Pruning drops the fixed occurrences only
+		pruned := baseline.Prune(report)
+		var entries []string
+		for _, entry := range pruned.Entries {
+			entries = append(entries, fmt.Sprintf("%s %s %d", entry.Rule, entry.Key, entry.Count))
+		}
+		want = []string{"hardcoded-string Welcome to our app 1", "undeclared-key Common.missing 1"}
+		if !reflect.DeepEqual(entries, want) {
+			t.Errorf("pruned entries = %q, want %q", entries, want)
+		}
+		if report := pruned.Apply(baselineResults(
+			`undeclared-key de src/page.tsx 4 "Common.missing"`,
+			`hardcoded-string - src/page.tsx 8 "Welcome to our app"`,
+		), "project"); report.Suppressed != 2 || len(report.Fixed) != 0 {
+			t.Errorf("pruned baseline: suppressed %d, fixed %+v, want 2 suppressed", report.Suppressed, report.Fixed)
+		}
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	issue := Issue{Rule: RuleHardcodedString, Translation: Translation{Key: "Welcome to our app", File: filepath.Join("project", "src", "page.tsx"), Line: 8}}
+	fingerprint := Fingerprint(issue, "project")
+	// Baselines are committed, so fingerprints must not change between
+	// releases: this is sha256("hardcoded-string\x00\x00src/page.tsx\x00Welcome to our app\x00")
+	if want := "fb8b7dda217e881d"; fingerprint != want {
+		t.Errorf("fingerprint = %s, want %s", fingerprint, want)
+	}
+
+	same := []Issue{
+		{Rule: issue.Rule, Translation: Translation{Key: "Welcome to our app", File: filepath.Join("project", "src", "page.tsx"), Line: 30}},
+		{Rule: issue.Rule, Translation: Translation{Key: " Welcome\n  to our app ", File: filepath.Join("project", "src", "page.tsx")}},
+		{Rule: issue.Rule, Translation: Translation{Key: "Welcome to our app", File: filepath.Join("project", ".", "src", "page.tsx")}},
+	}
+	for _, other := range same {
+		if got := Fingerprint(other, "project"); got != fingerprint {
+			t.Errorf("fingerprint of %+v = %s, want %s", other.Translation, got, fingerprint)
+		}
+	}
+	different := []Issue{
+		{Rule: RuleUnusedKey, Translation: issue.Translation},
+		{Rule: issue.Rule, Translation: Translation{Key: "Welcome to our app", Locale: "en", File: issue.File}},
+		{Rule: issue.Rule, Translation: Translation{Key: "Welcome to our app", File: filepath.Join("project", "src", "other.tsx")}},
+		{Rule: issue.Rule, Translation: Translation{Key: "Welcome to your app", File: issue.File}},
+	}
+	for _, other := range different {
+		if got := Fingerprint(other, "project"); got == fingerprint {
+			t.Errorf("fingerprint of %s %+v is the same", other.Rule, other.Translation)
+		}
+	}
+}
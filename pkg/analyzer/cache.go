@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultCacheDir is the directory, relative to the project root, where parse
+// results are cached between runs.
+const DefaultCacheDir = ".next-intl-analyzer-cache"
+
+// cacheSchema is bumped whenever the layout of a cache entry changes.
+const cacheSchema = "6"
+
+// Kinds of files stored in the parse cache.
+const (
+	cacheKindSource      = "source"
+	cacheKindTranslation = "translation"
+)
+
+// ParseCache stores per-file parse results. There is one entry per file,
+// named after the file path, which holds a hash of the file content, the
+// analyzer version and the parser configuration. A changed file, a new
+// release or a different configuration therefore never reuses a stale entry,
+// and replaces it rather than adding one. Entries are kept in memory as well
+// as on disk, so a long-lived Analyzer (watch mode, editors) only re-parses
+// changed files.
+type ParseCache struct {
+	dir        string
+	configHash string
+	// version is the analyzer version the entries are valid for
+	version string
+
+	mu     sync.RWMutex
+	memory map[string]cacheEntry
+}
+
+// cacheKey locates the entry of a file and tells whether it is up to date
+type cacheKey struct {
+	// name identifies the file, and hash what the entry was parsed from
+	name string
+	hash string
+}
+
+// cacheEntry is the stored parse result of a file
+type cacheEntry struct {
+	Hash string          `json:"hash"`
+	Data json.RawMessage `json:"data"`
+}
+
+// NewParseCache returns a cache that persists entries in dir. An empty dir
+// keeps the cache in memory only.
+func NewParseCache(dir string, configHash string) *ParseCache {
+	return &ParseCache{
+		dir:        dir,
+		configHash: configHash,
+		version:    Version,
+		memory:     make(map[string]cacheEntry),
+	}
+}
+
+// CleanCache removes the on-disk parse cache of the project at projectPath.
+func CleanCache(projectPath string) error {
+	return os.RemoveAll(filepath.Join(projectPath, DefaultCacheDir))
+}
+
+func (c *ParseCache) key(kind string, filePath string, content []byte) cacheKey {
+	return cacheKey{
+		name: hashParts([]string{kind, filePath}, nil),
+		hash: hashParts([]string{cacheSchema, c.version, c.configHash}, content),
+	}
+}
+
+func hashParts(parts []string, content []byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// get decodes the entry stored under key into v and reports whether it was
+// found up to date
+func (c *ParseCache) get(key cacheKey, v interface{}) bool {
+	c.mu.RLock()
+	entry, ok := c.memory[key.name]
+	c.mu.RUnlock()
+
+	if !ok {
+		if c.dir == "" {
+			return false
+		}
+		data, err := os.ReadFile(filepath.Join(c.dir, key.name+".json"))
+		if err != nil || json.Unmarshal(data, &entry) != nil {
+			return false
+		}
+	}
+
+	if entry.Hash != key.hash {
+		return false
+	}
+	if err := json.Unmarshal(entry.Data, v); err != nil {
+		return false
+	}
+
+	if !ok {
+		c.mu.Lock()
+		c.memory[key.name] = entry
+		c.mu.Unlock()
+	}
+	return true
+}
+
+// put stores v under key, replacing the previous entry of the file. Failing
+// to persist an entry is not an error; the file is simply parsed again on the
+// next run.
+func (c *ParseCache) put(key cacheKey, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	entry := cacheEntry{Hash: key.hash, Data: data}
+
+	c.mu.Lock()
+	c.memory[key.name] = entry
+	c.mu.Unlock()
+
+	if c.dir == "" {
+		return
+	}
+	if data, err = json.Marshal(entry); err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+
+	// Write through a temporary file so concurrent runs never observe a
+	// partially written entry.
+	tmp, err := os.CreateTemp(c.dir, key.name+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, key.name+".json")); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
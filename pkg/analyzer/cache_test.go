@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"context"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseCacheKeys(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte(`t('Common.save')`)
+	put := func(c *ParseCache) {
+		c.put(c.key(cacheKindSource, "src/page.tsx", content), "cached")
+	}
+	hit := func(c *ParseCache, content []byte) bool {
+		var v string
+		return c.get(c.key(cacheKindSource, "src/page.tsx", content), &v) && v == "cached"
+	}
+
+	put(NewParseCache(dir, "config"))
+
+	// A new cache reads the entry from disk
+	if !hit(NewParseCache(dir, "config"), content) {
+		t.Error("unchanged file missed")
+	}
+	if hit(NewParseCache(dir, "config"), []byte(`t('Common.cancel')`)) {
+		t.Error("changed content hit")
+	}
+	if hit(NewParseCache(dir, "other config"), content) {
+		t.Error("changed configuration hit")
+	}
+	release := NewParseCache(dir, "config")
+	release.version = "99.0.0"
+	if hit(release, content) {
+		t.Error("changed version hit")
+	}
+
+	// Entries of a file replace each other
+	c := NewParseCache(dir, "config")
+	for _, edit := range []string{"a", "b", "c"} {
+		c.put(c.key(cacheKindSource, "src/page.tsx", []byte(edit)), edit)
+	}
+	c.put(c.key(cacheKindTranslation, "messages/en.json", content), "messages")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || len(c.memory) != 2 {
+		t.Errorf("%d entries on disk and %d in memory for 2 files", len(entries), len(c.memory))
+	}
+}
+
+func TestParseCacheHitSkipsParsing(t *testing.T) {
+	page := []byte(`const t = useTranslations();
+export const title = t('Common.save');
+`)
+	fsys := fstest.MapFS{
+		"messages/en.json": {Data: []byte(`{"Common":{"save":"Save"}}`)},
+		"src/page.tsx":     {Data: page},
+	}
+	config := DefaultConfig()
+	cache := NewParseCache("", NewTranslationParser(config.ParserOptions()...).ConfigHash())
+
+	// An entry for the current content is used instead of parsing the file
+	cache.put(cache.key(cacheKindSource, "src/page.tsx", page), &SourceFile{Translations: map[string]Translation{
+		"Cached.key": {Key: "Cached.key", File: "src/page.tsx", Line: 1, Type: TypeTranslationCall},
+	}})
+	a, err := New(WithFS(fsys), WithConfig(config), WithCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := a.Analyze(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.UndeclaredTranslations) != 1 || results.UndeclaredTranslations[0].Key != "Cached.key" {
+		t.Errorf("undeclared = %v, want the cached key", results.UndeclaredTranslations)
+	}
+}
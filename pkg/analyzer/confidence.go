@@ -0,0 +1,83 @@
+package analyzer
+
+import "math"
+
+// Signals that contribute to the confidence of a hardcoded-string finding.
+// Their weights are defined in constants.go.
+const (
+	SignalTagContent       = "tag-content"       // text between JSX tags
+	SignalDisplayContext   = "display-context"   // literal in a prop, JSX expression, UI call or metadata field
+	SignalNoLetters        = "no-letters"        // no letter in any script
+	SignalAllowlisted      = "allowlisted"       // only names of the allowlist
+	SignalShortUIWord      = "short-ui-word"     // one of ShortUIWords
+	SignalTooShort         = "too-short"         // shorter than the minimum length of its script
+	SignalCodeCharacters   = "code-characters"   // brackets, operators or markup
+	SignalIdentifier       = "identifier"        // camelCase or snake_case word
+	SignalTechnicalPattern = "technical-pattern" // contains one of TechnicalPatterns
+	SignalUIPattern        = "ui-pattern"        // contains one of UIPatterns
+	SignalSentence         = "sentence"          // several words, capitalized and terminated
+	SignalMultiWord        = "multi-word"        // several words
+	SignalFewWords         = "few-words"         // long text with too few words for a phrase
+	SignalLetterRatio      = "letter-ratio"      // enough letters for its length
+	SignalLowLetterRatio   = "low-letter-ratio"  // too few letters for its length
+)
+
+// Signal is a heuristic that fired for a hardcoded-string candidate
+type Signal struct {
+	Name string `json:"name"`
+	// Detail is the matched pattern or measured value, when there is one
+	Detail string  `json:"detail,omitempty"`
+	Weight float64 `json:"weight"`
+}
+
+func defaultWeights() map[string]float64 {
+	return map[string]float64{
+		SignalTagContent:       WeightTagContent,
+		SignalDisplayContext:   WeightDisplayContext,
+		SignalNoLetters:        WeightNoLetters,
+		SignalAllowlisted:      WeightAllowlisted,
+		SignalShortUIWord:      WeightShortUIWord,
+		SignalTooShort:         WeightTooShort,
+		SignalCodeCharacters:   WeightCodeCharacters,
+		SignalIdentifier:       WeightIdentifier,
+		SignalTechnicalPattern: WeightTechnicalPattern,
+		SignalUIPattern:        WeightUIPattern,
+		SignalSentence:         WeightSentence,
+		SignalMultiWord:        WeightMultiWord,
+		SignalFewWords:         WeightFewWords,
+		SignalLetterRatio:      WeightLetterRatio,
+		SignalLowLetterRatio:   WeightLowLetterRatio,
+	}
+}
+
+// signal returns the named signal with its weight
+func (h *Heuristics) signal(name string, detail string) Signal {
+	return Signal{Name: name, Detail: detail, Weight: h.Weights[name]}
+}
+
+// Confidence returns the score in [0,1] of a candidate with the given
+// signals: the base confidence plus the weights of the signals, clamped and
+// rounded to two decimals.
+func (h *Heuristics) Confidence(signals []Signal) float64 {
+	score := h.Thresholds.BaseConfidence
+	for _, signal := range signals {
+		score += signal.Weight
+	}
+	score = math.Max(0, math.Min(1, score))
+	return math.Round(score*100) / 100
+}
+
+// addHardcodedString records a hardcoded-string candidate by text. Candidates
+// without any confidence are dropped, and the most confident occurrence of a
+// text wins.
+func (p *TranslationParser) addHardcodedString(candidates map[string]Translation, translation Translation, signals []Signal) {
+	translation.Signals = signals
+	translation.Confidence = p.heuristics.Confidence(signals)
+	if translation.Confidence <= 0 {
+		return
+	}
+	if existing, ok := candidates[translation.Key]; ok && existing.Confidence >= translation.Confidence {
+		return
+	}
+	candidates[translation.Key] = translation
+}
@@ -0,0 +1,260 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// ConfigFileName is the name of the configuration file looked up in the
+// project root
+const ConfigFileName = ".next-intl-analyzer.json"
+
+// Config configures the analysis of a project.
+//
+// Example:
+//
+//	{
+//	  "rules": { "hardcoded-string": "warning" },
+//	  "overrides": [
+//	    { "files": ["src/legacy/**"], "rules": { "hardcoded-string": "off" } }
+//	  ],
+//	  "failOn": "error",
+//	  "minConfidence": 0.6,
+//	  "heuristics": { "allowlist": ["Acme"] }
+//	}
+type Config struct {
+	// Rules overrides the default severity of rules by rule ID
+	Rules map[string]Severity `json:"rules,omitempty"`
+	// Overrides changes rule severities for files matching glob patterns.
+	// Later overrides take precedence over earlier ones.
+	Overrides []RuleOverride `json:"overrides,omitempty"`
+	// FailOn is the lowest severity that makes the analysis fail
+	FailOn *Severity `json:"failOn,omitempty"`
+	// MinConfidence is the lowest confidence, between 0 and 1, of the
+	// hardcoded strings that are reported
+	MinConfidence *float64 `json:"minConfidence,omitempty"`
+	// Heuristics changes the lists and thresholds of the hardcoded-string
+	// detection, see HeuristicsConfig
+	Heuristics *HeuristicsConfig `json:"heuristics,omitempty"`
+	// Projects turns the project into a workspace of several projects, such
+	// as the apps and packages of a monorepo, analyzed separately
+	Projects []ProjectConfig `json:"projects,omitempty"`
+	// KeyManifest is the key manifest written by generate-keys, relative to
+	// the project root. Keys referenced through its constants count as used.
+	KeyManifest string `json:"keyManifest,omitempty"`
+	// Include limits the source files to those matching one of the glob
+	// patterns, see MatchGlob
+	Include []string `json:"include,omitempty"`
+	// Exclude skips the source and message files, and the directories,
+	// matching one of the glob patterns
+	Exclude []string `json:"exclude,omitempty"`
+	// Gitignore disables the .gitignore files and .git/info/exclude when
+	// false; they apply by default
+	Gitignore *bool `json:"gitignore,omitempty"`
+	// FollowSymlinks walks symbolic links to files and directories, which
+	// are skipped by default
+	FollowSymlinks bool `json:"followSymlinks,omitempty"`
+}
+
+// RuleOverride sets rule severities for the files matching any of Files.
+// Patterns are relative to the project root, see MatchGlob.
+type RuleOverride struct {
+	Files []string            `json:"files"`
+	Rules map[string]Severity `json:"rules"`
+}
+
+// ProjectConfig is a project of a workspace. Sources and Messages are the
+// directories, relative to the workspace root, holding its source files and
+// its message files. Message directories may be shared by several projects.
+type ProjectConfig struct {
+	Name     string   `json:"name"`
+	Sources  []string `json:"sources"`
+	Messages []string `json:"messages"`
+}
+
+// DefaultConfig returns the configuration used when a project has no
+// configuration file
+func DefaultConfig() *Config {
+	return &Config{}
+}
+
+// LoadConfig reads and validates a configuration file
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config %s: %w", path, err)
+	}
+	return parseConfig(path, content)
+}
+
+// LoadProjectConfigFS is LoadProjectConfig for a project read from fsys, see
+// WithFS
+func LoadProjectConfigFS(fsys fs.FS) (*Config, error) {
+	content, err := fs.ReadFile(fsys, ConfigFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading config %s: %w", ConfigFileName, err)
+	}
+	return parseConfig(ConfigFileName, content)
+}
+
+// parseConfig parses and validates the content of the configuration file at
+// path
+func parseConfig(path string, content []byte) (*Config, error) {
+	config := DefaultConfig()
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("error parsing config %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// LoadProjectConfig reads ConfigFileName from the project root, falling back
+// to DefaultConfig when the project has none
+func LoadProjectConfig(projectPath string) (*Config, error) {
+	config, err := LoadConfig(filepath.Join(projectPath, ConfigFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultConfig(), nil
+	}
+	return config, err
+}
+
+func (c *Config) validate() error {
+	for id := range c.Rules {
+		if _, ok := LookupRule(id); !ok {
+			return fmt.Errorf("unknown rule %q", id)
+		}
+	}
+	if _, err := c.Heuristics.Apply(DefaultHeuristics()); err != nil {
+		return fmt.Errorf("heuristics: %w", err)
+	}
+	if c.MinConfidence != nil && (*c.MinConfidence < 0 || *c.MinConfidence > 1) {
+		return fmt.Errorf("minConfidence %v is not between 0 and 1", *c.MinConfidence)
+	}
+	names := make(map[string]bool, len(c.Projects))
+	for i, project := range c.Projects {
+		switch {
+		case project.Name == "":
+			return fmt.Errorf("project %d has no name", i)
+		case names[project.Name]:
+			return fmt.Errorf("duplicate project %q", project.Name)
+		case len(project.Sources) == 0 || len(project.Messages) == 0:
+			return fmt.Errorf("project %q needs sources and messages", project.Name)
+		}
+		names[project.Name] = true
+		for _, dir := range append(append([]string{}, project.Sources...), project.Messages...) {
+			if !fs.ValidPath(path.Clean(filepath.ToSlash(dir))) {
+				return fmt.Errorf("project %q: directory %q is not relative to the workspace root", project.Name, dir)
+			}
+		}
+	}
+	for i, override := range c.Overrides {
+		if len(override.Files) == 0 {
+			return fmt.Errorf("override %d has no files", i)
+		}
+		for id := range override.Rules {
+			if _, ok := LookupRule(id); !ok {
+				return fmt.Errorf("unknown rule %q in override %d", id, i)
+			}
+		}
+	}
+	return nil
+}
+
+// SeverityFor returns the severity of rule for a file, given relative to the
+// project root with forward slashes
+func (c *Config) SeverityFor(rule string, file string) Severity {
+	severity := SeverityOff
+	if r, ok := LookupRule(rule); ok {
+		severity = r.DefaultSeverity
+	}
+	if configured, ok := c.Rules[rule]; ok {
+		severity = configured
+	}
+
+	for _, override := range c.Overrides {
+		configured, ok := override.Rules[rule]
+		if !ok {
+			continue
+		}
+		for _, pattern := range override.Files {
+			if MatchGlob(pattern, file) {
+				severity = configured
+				break
+			}
+		}
+	}
+	return severity
+}
+
+// FailThreshold returns the lowest severity that makes the analysis fail
+func (c *Config) FailThreshold() Severity {
+	if c.FailOn != nil {
+		return *c.FailOn
+	}
+	return SeverityError
+}
+
+// UsesGitignore reports whether file discovery skips the files ignored by
+// git
+func (c *Config) UsesGitignore() bool {
+	return c.Gitignore == nil || *c.Gitignore
+}
+
+// included reports whether the source file name matches Include
+func (c *Config) included(name string) bool {
+	if len(c.Include) == 0 {
+		return true
+	}
+	for _, pattern := range c.Include {
+		if MatchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded reports whether the file or directory name matches Exclude
+func (c *Config) excluded(name string) bool {
+	for _, pattern := range c.Exclude {
+		if MatchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfidenceThreshold returns the lowest confidence of reported hardcoded
+// strings
+func (c *Config) ConfidenceThreshold() float64 {
+	if c.MinConfidence != nil {
+		return *c.MinConfidence
+	}
+	return DefaultMinConfidence
+}
+
+// HeuristicsOf returns the default heuristics changed by the configuration.
+// The configuration is validated when it is loaded, so invalid heuristics
+// settings fall back to the defaults here.
+func (c *Config) HeuristicsOf() Heuristics {
+	heuristics, err := c.Heuristics.Apply(DefaultHeuristics())
+	if err != nil {
+		return DefaultHeuristics()
+	}
+	return heuristics
+}
+
+// ParserOptions returns the options of the parser used for this
+// configuration
+func (c *Config) ParserOptions() []ParserOption {
+	return []ParserOption{WithHeuristics(c.HeuristicsOf())}
+}
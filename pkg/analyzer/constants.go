@@ -8,39 +8,37 @@ var ShortUIWords = []string{
 	"Sort", "View", "Hide", "Show", "More", "Less", "All", "None",
 }
 
-// Technical patterns that indicate code/technical content (should NOT be translated)
+// Technical patterns that indicate code/technical content (should NOT be translated).
+// Patterns made of letters only match whole words, so "in" does not match
+// "string". Keywords that are also common English words ("in", "of", "for",
+// "return", "new", ...) are left out because they reject genuine text.
 var TechnicalPatterns = []string{
 	// HTML/JSX attributes
 	"className", "id=", "href=", "src=", "alt=", "title=", "type=", "value=",
 	"placeholder=", "aria-", "data-", "role=", "tabindex=", "disabled=",
 	"readonly=", "required=", "maxlength=", "minlength=", "pattern=",
-	
+
 	// React/JSX patterns
-	"onClick", "onChange", "onSubmit", "onLoad", "onBlur", "onFocus", "onKey",
+	"onClick", "onChange", "onSubmit", "onLoad", "onBlur", "onFocus", "onKeyDown", "onKeyUp",
 	"useState", "useEffect", "useCallback", "useMemo", "useRef", "useContext",
-	"import", "export", "const", "let", "var", "function", "return", "if", "else",
-	"for", "while", "switch", "case", "default", "break", "continue",
-	"true", "false", "null", "undefined", "NaN", "Infinity",
-	
+	"const", "var", "true", "false", "null", "undefined", "NaN", "Infinity",
+
 	// Common variable names and technical terms
-	"props", "state", "ref", "key", "index", "item", "element", "component",
-	"handler", "callback", "event", "e.target", "e.preventDefault",
-	"children", "className=", "style=", "id=", "name=", "onClick=",
+	"props", "ref", "e.target", "e.preventDefault",
+	"className=", "style=", "name=", "onClick=",
 	"onChange=", "onSubmit=", "width=", "height=", "size=", "color=",
-	
+
 	// File extensions and paths
 	".js", ".jsx", ".ts", ".tsx", ".css", ".scss", ".json", ".md",
 	"http://", "https://", "www.", ".com", ".org", ".net",
-	
+
 	// Code patterns
-	"console.log", "debugger", "throw", "catch", "finally", "try",
-	"new ", "instanceof", "typeof", "delete", "in", "of",
-	">", "</", "<", "/>", "={", "{}", "()", "[]",
-	
+	"console.log", "debugger", "instanceof", "typeof",
+	"={", "{}", "()", "[]",
+
 	// JavaScript keywords
-	"await", "async", "class", "super", "this", "prototype", "constructor",
-	"extends", "implements", "interface", "enum", "public", "private", "protected",
-	
+	"await", "async", "prototype", "constructor", "enum",
+
 	// Common code tokens
 	"=>{", "()=>", "=>(", "=>{}", "...props", "...rest", "className={`", "className={",
 }
@@ -61,20 +59,91 @@ var UIPatterns = []string{
 	"Previous", "Next", "Back to", "Return to", "Go to", "Navigate to",
 }
 
+// Component props and object properties whose string values are displayed to
+// the user
+var TranslatableProps = []string{
+	"title", "alt", "placeholder", "aria-label", "aria-description", "description",
+	"label", "helperText", "tooltip", "caption", "heading", "subheading", "subtitle",
+	"message", "text", "hint", "errorMessage", "emptyText", "emptyMessage",
+	"confirmText", "cancelText", "okText", "buttonText", "submitText", "legend",
+}
+
+// Functions whose string arguments are displayed to the user. A "window."
+// prefix is ignored.
+var UICallSites = []string{
+	"alert", "confirm", "prompt",
+	"toast", "toast.success", "toast.error", "toast.info", "toast.warning",
+	"toast.warn", "toast.loading", "toast.message",
+	"message.success", "message.error", "message.info", "message.warning", "message.loading",
+}
+
+// Fields of Next.js metadata exports that end up in the page head, in
+// addition to TranslatableProps
+var MetadataFields = []string{
+	"title", "description", "default", "absolute", "applicationName", "siteName",
+}
+
 // Common punctuation marks that might appear in user-facing text
 var CommonPunctuation = []string{
 	"!", "?", ".", ",", ":", ";", "-", "—", "–", "…",
+	// Full-width CJK punctuation
+	"。", "、", "，", "！", "？", "：", "；", "・", "「", "」", "『", "』",
+	// Arabic punctuation
+	"،", "؛", "؟",
 }
 
-// Minimum length for text to be considered for translation
+// Minimum length in characters for text to be considered for translation
 const MinTextLength = 3
 
-// Alphabetic ratio thresholds for different text lengths
+// Letter ratio thresholds for different text lengths, in characters. They
+// apply to Latin text and to other scripts that separate words with spaces
+// (Cyrillic, Greek, Arabic, Hebrew, ...); letters of any script count.
 const (
-	LongTextThreshold   = 10  // Characters
-	MediumTextThreshold = 5   // Characters
+	LongTextThreshold   = 10   // Characters
+	MediumTextThreshold = 5    // Characters
 	LongTextRatio       = 0.6  // 60% alphabetic (lowered to reduce false positives)
 	MediumTextRatio     = 0.6  // 60% alphabetic (increased to be more strict)
 	ShortTextRatio      = 0.75 // 75% alphabetic (increased to be more strict)
 	MinWordsForSentence = 3    // Minimum words for a phrase to be considered a sentence
-) 
\ No newline at end of file
+)
+
+// Minimum words for a phrase in a non-Latin alphabetic script (Cyrillic,
+// Greek, Arabic, Hebrew, ...) to be considered a sentence. UIPatterns only
+// lists English phrases and such text is never a JavaScript identifier, so
+// two words are enough.
+const MinWordsForNonLatinSentence = 2
+
+// Thresholds for CJK text (Han, Hiragana, Katakana, Hangul). A single
+// character often carries a whole word and Chinese and Japanese are written
+// without spaces, so shorter texts qualify and no word count is required.
+const (
+	MinCJKTextLength     = 2   // Characters, e.g. 保存 ("save")
+	CJKLongTextThreshold = 5   // Characters
+	CJKLongTextRatio     = 0.5 // 50% letters, leaving room for numbers and punctuation
+	CJKShortTextRatio    = 0.6 // 60% letters
+)
+
+// Confidence scoring of hardcoded strings. A candidate starts at
+// BaseConfidence and every heuristic signal that fires adds its weight (see
+// Heuristics.Confidence). Candidates below the minimum confidence, DefaultMinConfidence
+// unless configured, are not reported.
+const (
+	BaseConfidence       = 0.5
+	DefaultMinConfidence = 0.5
+
+	WeightTagContent       = 0.05
+	WeightDisplayContext   = 0.3
+	WeightNoLetters        = -1.0
+	WeightAllowlisted      = -1.0
+	WeightShortUIWord      = 0.4
+	WeightTooShort         = -0.5
+	WeightCodeCharacters   = -0.5
+	WeightIdentifier       = -0.4
+	WeightTechnicalPattern = -0.3
+	WeightUIPattern        = 0.3
+	WeightSentence         = 0.3
+	WeightMultiWord        = 0.2
+	WeightFewWords         = -0.2
+	WeightLetterRatio      = 0.1
+	WeightLowLetterRatio   = -0.3
+)
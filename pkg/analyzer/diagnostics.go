@@ -0,0 +1,20 @@
+package analyzer
+
+// Diagnostic is a problem the analysis ran into without failing, such as a
+// message file that cannot be parsed or a suppression comment naming an
+// unknown rule. Diagnostics are collected in AnalysisResult.Diagnostics
+// instead of being printed; it is up to the caller to show them.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	// File and Line locate the problem when it concerns a file. Line is 0
+	// when the problem concerns the whole file.
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// String returns the message of the diagnostic, which names the file it
+// concerns
+func (d Diagnostic) String() string {
+	return d.Message
+}
@@ -0,0 +1,213 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// IssueStatus classifies an issue of a revision compared to a base revision
+type IssueStatus string
+
+const (
+	// StatusNew is an issue that the base revision does not have
+	StatusNew IssueStatus = "new"
+	// StatusFixed is an issue of the base revision that is gone
+	StatusFixed IssueStatus = "fixed"
+	// StatusUnchanged is an issue of both revisions
+	StatusUnchanged IssueStatus = "unchanged"
+)
+
+// RevisionDiff compares the analysis of a head revision with the analysis of
+// a base revision. Issues are matched like in watch mode, by rule, locale,
+// file and key, so moving code within a file does not make an issue new, and
+// issues of renamed keys are matched under their new key.
+type RevisionDiff struct {
+	Base string
+	Head string
+
+	New       []Issue
+	Fixed     []Issue
+	Unchanged []Issue
+
+	// AddedKeys and RemovedKeys list the message keys, namespaces excluded,
+	// declared in only one of the revisions. Renamed keys are listed in
+	// RenamedKeys instead.
+	AddedKeys   []KeyChange
+	RemovedKeys []KeyChange
+	RenamedKeys []KeyRename
+
+	status map[string]IssueStatus
+}
+
+// KeyChange is a message key added or removed in the locales listed
+type KeyChange struct {
+	Key     string   `json:"key"`
+	Locales []string `json:"locales"`
+}
+
+// KeyRename is a message key removed while another key with the same
+// messages in the same locales was added
+type KeyRename struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Locales []string `json:"locales"`
+}
+
+// DiffResults compares the head results and declared keys, see
+// Analyzer.Messages, with those of the base revision
+func DiffResults(base *AnalysisResult, head *AnalysisResult, baseMessages map[string]map[string]Translation, headMessages map[string]map[string]Translation) *RevisionDiff {
+	diff := &RevisionDiff{status: make(map[string]IssueStatus)}
+
+	baseKeys := MessageKeys(baseMessages)
+	headKeys := MessageKeys(headMessages)
+	removed := missingLocales(baseKeys, headKeys)
+	added := missingLocales(headKeys, baseKeys)
+
+	// A key removed from every locale and a key added to every locale with the
+	// same messages are a rename, unless several keys share these messages
+	renames := make(map[string]string)
+	removedBySignature := groupBySignature(removed, headKeys, baseMessages)
+	addedBySignature := groupBySignature(added, baseKeys, headMessages)
+	for signature, from := range removedBySignature {
+		to := addedBySignature[signature]
+		if len(from) != 1 || len(to) != 1 {
+			continue
+		}
+		diff.RenamedKeys = append(diff.RenamedKeys, KeyRename{From: from[0], To: to[0], Locales: added[to[0]]})
+		renames[from[0]] = to[0]
+		delete(removed, from[0])
+		delete(added, to[0])
+	}
+	diff.AddedKeys = keyChanges(added)
+	diff.RemovedKeys = keyChanges(removed)
+	sort.Slice(diff.RenamedKeys, func(i, j int) bool { return diff.RenamedKeys[i].From < diff.RenamedKeys[j].From })
+
+	// Issues of renamed keys are matched under their new key, so that a
+	// rename does not make them new
+	baseIssues := base.Issues()
+	renamedIssues := make([]Issue, len(baseIssues))
+	original := make(map[string]Issue, len(baseIssues))
+	for i, issue := range baseIssues {
+		renamedIssues[i] = issue
+		if to, ok := renames[issue.Key]; ok && (issue.Rule == RuleUnusedKey || issue.Rule == RuleUndeclaredKey || issue.Rule == RuleMissingTranslation) {
+			renamedIssues[i].Key = to
+		}
+		original[renamedIssues[i].ID()] = issue
+	}
+
+	headIssues := head.Issues()
+	var fixed []Issue
+	diff.New, fixed = DiffIssues(renamedIssues, headIssues)
+	for _, issue := range fixed {
+		issue = original[issue.ID()]
+		diff.Fixed = append(diff.Fixed, issue)
+		diff.status[issue.ID()] = StatusFixed
+	}
+	for _, issue := range diff.New {
+		diff.status[issue.ID()] = StatusNew
+	}
+	for _, issue := range headIssues {
+		if _, ok := diff.status[issue.ID()]; !ok {
+			diff.status[issue.ID()] = StatusUnchanged
+			diff.Unchanged = append(diff.Unchanged, issue)
+		}
+	}
+	return diff
+}
+
+// Status returns the status of an issue of the head or base revision
+func (d *RevisionDiff) Status(issue Issue) IssueStatus {
+	return d.status[issue.ID()]
+}
+
+// Fails reports whether any new issue is at least as severe as threshold
+func (d *RevisionDiff) Fails(threshold Severity) bool {
+	if threshold == SeverityOff {
+		return false
+	}
+	for _, issue := range d.New {
+		if issue.Severity >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// HasChanges reports whether the revisions differ in issues or keys
+func (d *RevisionDiff) HasChanges() bool {
+	return len(d.New) > 0 || len(d.Fixed) > 0 || len(d.AddedKeys) > 0 || len(d.RemovedKeys) > 0 || len(d.RenamedKeys) > 0
+}
+
+// MessageKeys returns the sorted locales declaring each key of messages, as
+// returned by Analyzer.Messages. Namespaces, keys that have nested keys, are
+// left out.
+func MessageKeys(messages map[string]map[string]Translation) map[string][]string {
+	keys := make(map[string][]string)
+	for locale, declared := range messages {
+		namespaces := make(map[string]bool)
+		for key := range declared {
+			for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+				namespaces[key[:i]] = true
+			}
+		}
+		for key := range declared {
+			if !namespaces[key] {
+				keys[key] = append(keys[key], locale)
+			}
+		}
+	}
+	for _, locales := range keys {
+		sort.Strings(locales)
+	}
+	return keys
+}
+
+// missingLocales returns, for every key of keys, the locales of keys that
+// other lacks
+func missingLocales(keys map[string][]string, other map[string][]string) map[string][]string {
+	missing := make(map[string][]string)
+	for key, locales := range keys {
+		present := make(map[string]bool, len(other[key]))
+		for _, locale := range other[key] {
+			present[locale] = true
+		}
+		for _, locale := range locales {
+			if !present[locale] {
+				missing[key] = append(missing[key], locale)
+			}
+		}
+	}
+	return missing
+}
+
+// groupBySignature groups the changed keys that the other revision does not
+// declare at all by their messages in every locale. Keys without any message
+// are left out, as they cannot be told apart.
+func groupBySignature(changed map[string][]string, other map[string][]string, messages map[string]map[string]Translation) map[string][]string {
+	groups := make(map[string][]string)
+	for key, locales := range changed {
+		if _, ok := other[key]; ok {
+			continue
+		}
+		var signature strings.Builder
+		hasMessage := false
+		for _, locale := range locales {
+			value := messages[locale][key].Value
+			hasMessage = hasMessage || value != ""
+			signature.WriteString(locale + "\x00" + value + "\x00")
+		}
+		if hasMessage {
+			groups[signature.String()] = append(groups[signature.String()], key)
+		}
+	}
+	return groups
+}
+
+func keyChanges(keys map[string][]string) []KeyChange {
+	changes := make([]KeyChange, 0, len(keys))
+	for key, locales := range keys {
+		changes = append(changes, KeyChange{Key: key, Locales: locales})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+)
+
+// testMessages returns the declared keys of messages, keyed by locale then
+// key, in the form returned by Analyzer.Messages
+func testMessages(messages map[string]map[string]string) map[string]map[string]Translation {
+	declared := make(map[string]map[string]Translation, len(messages))
+	for locale, values := range messages {
+		declared[locale] = make(map[string]Translation, len(values))
+		for key, value := range values {
+			declared[locale][key] = Translation{Key: key, Locale: locale, File: "messages/" + locale + ".json", Value: value, Declared: true}
+		}
+	}
+	return declared
+}
+
+// unused returns results with a single unused key in each locale given
+func unused(key string, locales ...string) *AnalysisResult {
+	results := newAnalysisResult()
+	for _, locale := range locales {
+		results.UnusedTranslations = append(results.UnusedTranslations, Translation{Key: key, Locale: locale, File: "messages/" + locale + ".json"})
+	}
+	return results
+}
+
+func issueKeys(issues []Issue) []string {
+	var keys []string
+	for _, issue := range issues {
+		keys = append(keys, issue.Rule+" "+issue.Locale+" "+issue.Key)
+	}
+	return keys
+}
+
+func TestDiffResults(t *testing.T) {
+	tests := []struct {
+		name       string
+		base, head map[string]map[string]string
+		// baseResults and headResults default to no issues
+		baseResults, headResults *AnalysisResult
+
+		renamed          []KeyRename
+		added, removed   []KeyChange
+		new, fixed, same []string
+	}{
+		{
+			name:        "clean rename",
+			base:        map[string]map[string]string{"en": {"Old.title": "Welcome"}, "de": {"Old.title": "Willkommen"}},
+			head:        map[string]map[string]string{"en": {"New.title": "Welcome"}, "de": {"New.title": "Willkommen"}},
+			baseResults: unused("Old.title", "de", "en"),
+			headResults: unused("New.title", "de", "en"),
+			renamed:     []KeyRename{{From: "Old.title", To: "New.title", Locales: []string{"de", "en"}}},
+			// Issues of the renamed key are matched under the new key
+			same: []string{"unused-key de New.title", "unused-key en New.title"},
+		},
+		{
+			name:        "rename fixing and raising issues",
+			base:        map[string]map[string]string{"en": {"Old.title": "Welcome"}},
+			head:        map[string]map[string]string{"en": {"New.title": "Welcome"}},
+			baseResults: unused("Old.title", "en"),
+			headResults: &AnalysisResult{UndeclaredTranslations: []Translation{{Key: "Old.title", Locale: "en", File: "src/page.tsx", Line: 3}}},
+			renamed:     []KeyRename{{From: "Old.title", To: "New.title", Locales: []string{"en"}}},
+			// Fixed issues keep their key in the base revision
+			new:   []string{"undeclared-key en Old.title"},
+			fixed: []string{"unused-key en Old.title"},
+		},
+		{
+			name:        "rename of a key missing in a locale",
+			base:        map[string]map[string]string{"en": {"Old.title": "Welcome"}, "de": {}},
+			head:        map[string]map[string]string{"en": {"New.title": "Welcome"}, "de": {}},
+			baseResults: &AnalysisResult{MissingTranslations: []Translation{{Key: "Old.title", Locale: "de", File: "messages/en.json", Line: 1}}},
+			headResults: &AnalysisResult{MissingTranslations: []Translation{{Key: "New.title", Locale: "de", File: "messages/en.json", Line: 1}}},
+			renamed:     []KeyRename{{From: "Old.title", To: "New.title", Locales: []string{"en"}}},
+			same:        []string{"missing-translation de New.title"},
+		},
+		{
+			name:        "ambiguous signature",
+			base:        map[string]map[string]string{"en": {"A.one": "Save", "A.two": "Save"}},
+			head:        map[string]map[string]string{"en": {"B.one": "Save", "B.two": "Save"}},
+			baseResults: unused("A.one", "en"),
+			headResults: unused("B.one", "en"),
+			added:       []KeyChange{{Key: "B.one", Locales: []string{"en"}}, {Key: "B.two", Locales: []string{"en"}}},
+			removed:     []KeyChange{{Key: "A.one", Locales: []string{"en"}}, {Key: "A.two", Locales: []string{"en"}}},
+			new:         []string{"unused-key en B.one"},
+			fixed:       []string{"unused-key en A.one"},
+		},
+		{
+			name:    "partial-locale rename",
+			base:    map[string]map[string]string{"en": {"A": "Save"}, "de": {"A": "Speichern"}},
+			head:    map[string]map[string]string{"en": {"B": "Save"}, "de": {"A": "Speichern"}},
+			added:   []KeyChange{{Key: "B", Locales: []string{"en"}}},
+			removed: []KeyChange{{Key: "A", Locales: []string{"en"}}},
+		},
+		{
+			name:    "changed message",
+			base:    map[string]map[string]string{"en": {"A": "Save"}},
+			head:    map[string]map[string]string{"en": {"B": "Save changes"}},
+			added:   []KeyChange{{Key: "B", Locales: []string{"en"}}},
+			removed: []KeyChange{{Key: "A", Locales: []string{"en"}}},
+		},
+		{
+			name: "namespaces without messages",
+			base: map[string]map[string]string{"en": {"Old": "", "Old.a": "A"}},
+			head: map[string]map[string]string{"en": {"New": "", "New.a": "A"}},
+			// Namespaces are not keys
+			renamed: []KeyRename{{From: "Old.a", To: "New.a", Locales: []string{"en"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseResults, headResults := tt.baseResults, tt.headResults
+			if baseResults == nil {
+				baseResults = newAnalysisResult()
+			}
+			if headResults == nil {
+				headResults = newAnalysisResult()
+			}
+			diff := DiffResults(baseResults, headResults, testMessages(tt.base), testMessages(tt.head))
+
+			// Nil and empty lists print alike
+			if fmt.Sprint(diff.RenamedKeys) != fmt.Sprint(tt.renamed) {
+				t.Errorf("renamed = %v, want %v", diff.RenamedKeys, tt.renamed)
+			}
+			if fmt.Sprint(diff.AddedKeys) != fmt.Sprint(tt.added) {
+				t.Errorf("added = %v, want %v", diff.AddedKeys, tt.added)
+			}
+			if fmt.Sprint(diff.RemovedKeys) != fmt.Sprint(tt.removed) {
+				t.Errorf("removed = %v, want %v", diff.RemovedKeys, tt.removed)
+			}
+			for _, status := range []struct {
+				name      string
+				got, want []string
+			}{
+				{"new", issueKeys(diff.New), tt.new},
+				{"fixed", issueKeys(diff.Fixed), tt.fixed},
+				{"unchanged", issueKeys(diff.Unchanged), tt.same},
+			} {
+				if fmt.Sprint(status.got) != fmt.Sprint(status.want) {
+					t.Errorf("%s issues = %v, want %v", status.name, status.got, status.want)
+				}
+			}
+			for _, issue := range diff.Fixed {
+				if diff.Status(issue) != StatusFixed {
+					t.Errorf("status of fixed %s = %q", issue.Key, diff.Status(issue))
+				}
+			}
+			for _, issue := range diff.New {
+				if diff.Status(issue) != StatusNew {
+					t.Errorf("status of new %s = %q", issue.Key, diff.Status(issue))
+				}
+			}
+		})
+	}
+}
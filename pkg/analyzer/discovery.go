@@ -0,0 +1,335 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// File discovery walks the project like git does: files matched by the
+// .gitignore files of the project, nested ones included, and by
+// .git/info/exclude are skipped, as are the files matching Config.Exclude.
+
+// ignoreFileName is the name of the ignore files read in every directory
+const ignoreFileName = ".gitignore"
+
+// gitExcludeFile is the ignore file of the repository that is not committed
+const gitExcludeFile = ".git/info/exclude"
+
+// findFiles returns the files below roots accepted by match, as reported in
+// results. Directories named skipDirs are not entered. When the analysis is
+// limited to a list of files, see WithFiles, only those are matched; they
+// are named explicitly, so ignore files do not apply to them.
+//
+// Files are in lexical order. Symbolic links are skipped unless
+// Config.FollowSymlinks is set. Links are then resolved after walking the
+// project, so that files are named by their own path rather than a link to
+// them: a linked directory is walked unless it was already, which also
+// breaks link cycles, and a linked file is kept unless it was found already.
+func (a *Analyzer) findFiles(roots []string, match func(name string) bool, skipDirs ...string) ([]string, error) {
+	var files []string
+
+	if a.files != nil {
+		for _, name := range a.files {
+			if match(name) && !a.config.excluded(name) && (roots == nil || inRoots(name, roots)) {
+				files = append(files, a.filePath(name))
+			}
+		}
+		sort.Strings(files)
+		return files, nil
+	}
+
+	if roots == nil {
+		roots = []string{"."}
+	}
+	w := &fileWalker{a: a, match: match, skipDirs: skipDirs, seen: make(map[string]bool)}
+	for _, root := range roots {
+		rules, err := w.rulesAbove(root)
+		if err != nil {
+			return nil, err
+		}
+		info, err := fs.Stat(a.fsys, root)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if match(root) && !w.seen[root] {
+				w.seen[root] = true
+				w.files = append(w.files, a.filePath(root))
+			}
+			continue
+		}
+		// Roots may overlap, such as a project and its messages
+		if w.walked(info) {
+			continue
+		}
+		if err := w.walk(root, rules); err != nil {
+			return nil, err
+		}
+	}
+
+	// Links are queued while walking, including links found in linked
+	// directories
+	for i := 0; i < len(w.links); i++ {
+		link := w.links[i]
+		if w.walked(link.info) {
+			continue
+		}
+		if err := w.walk(link.name, link.rules); err != nil {
+			return nil, err
+		}
+	}
+	if len(w.linkedFiles) > 0 {
+		var found []fs.FileInfo
+		for name := range w.seen {
+			if info, err := fs.Stat(a.fsys, name); err == nil {
+				found = append(found, info)
+			}
+		}
+		for _, link := range w.linkedFiles {
+			if !sameFile(found, link.info) {
+				found = append(found, link.info)
+				w.files = append(w.files, a.filePath(link.name))
+			}
+		}
+	}
+
+	// Walks list "a/b" before "a-c", which sorts first as "-" is below "/"
+	sort.Strings(w.files)
+	return w.files, nil
+}
+
+// fileWalker collects the files of a findFiles call
+type fileWalker struct {
+	a        *Analyzer
+	match    func(name string) bool
+	skipDirs []string
+
+	files []string
+	seen  map[string]bool
+	// dirs are the directories walked so far, only recorded when links are
+	// followed
+	dirs []fs.FileInfo
+	// links are the linked directories left to walk
+	links []walkLink
+	// linkedFiles are the links to matching files, which are not in seen
+	linkedFiles []walkLink
+}
+
+// walkLink is a symbolic link, with the ignore rules of the directory holding
+// the link
+type walkLink struct {
+	name  string
+	info  fs.FileInfo
+	rules []ignoreRule
+}
+
+// walked records dir as walked, and reports whether it already was
+func (w *fileWalker) walked(info fs.FileInfo) bool {
+	if !w.a.config.FollowSymlinks {
+		return false
+	}
+	if sameFile(w.dirs, info) {
+		return true
+	}
+	w.dirs = append(w.dirs, info)
+	return false
+}
+
+// sameFile reports whether info describes one of files
+func sameFile(files []fs.FileInfo, info fs.FileInfo) bool {
+	for _, file := range files {
+		if os.SameFile(file, info) {
+			return true
+		}
+	}
+	return false
+}
+
+// walk collects the files of dir, whose parent directories have the ignore
+// rules given
+func (w *fileWalker) walk(dir string, rules []ignoreRule) error {
+	entries, err := fs.ReadDir(w.a.fsys, dir)
+	if err != nil {
+		return err
+	}
+	if rules, err = w.readIgnoreFile(rules, dir, path.Join(dir, ignoreFileName)); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := path.Join(dir, entry.Name())
+		isDir := entry.IsDir()
+		var info fs.FileInfo
+
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if !w.a.config.FollowSymlinks {
+				continue
+			}
+			// Broken links are skipped
+			if info, err = fs.Stat(w.a.fsys, name); err != nil {
+				continue
+			}
+			isDir = info.IsDir()
+		}
+
+		if isDir {
+			if w.skipDir(entry.Name()) || w.a.config.excluded(name) || ignored(rules, name, true) {
+				continue
+			}
+			if info != nil {
+				w.links = append(w.links, walkLink{name: name, info: info, rules: rules})
+				continue
+			}
+			if w.a.config.FollowSymlinks {
+				if info, err = entry.Info(); err != nil {
+					return err
+				}
+				if w.walked(info) {
+					continue
+				}
+			}
+			if err := w.walk(name, rules); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if w.seen[name] || !w.match(name) || w.a.config.excluded(name) || ignored(rules, name, false) {
+			continue
+		}
+		if info != nil {
+			w.linkedFiles = append(w.linkedFiles, walkLink{name: name, info: info, rules: rules})
+			continue
+		}
+		w.seen[name] = true
+		w.files = append(w.files, w.a.filePath(name))
+	}
+	return nil
+}
+
+func (w *fileWalker) skipDir(name string) bool {
+	for _, dir := range w.skipDirs {
+		if name == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesAbove returns the ignore rules of the directories above root, which
+// apply to root without being walked
+func (w *fileWalker) rulesAbove(root string) ([]ignoreRule, error) {
+	rules, err := w.readIgnoreFile(nil, ".", gitExcludeFile)
+	if err != nil || root == "." {
+		return rules, err
+	}
+	// Outermost first
+	var dirs []string
+	for dir := path.Dir(root); dir != "."; dir = path.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+	}
+	for _, dir := range append([]string{"."}, dirs...) {
+		if rules, err = w.readIgnoreFile(rules, dir, path.Join(dir, ignoreFileName)); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// readIgnoreFile appends the rules of the ignore file name, whose patterns
+// are relative to dir, to rules. A missing file adds no rules.
+func (w *fileWalker) readIgnoreFile(rules []ignoreRule, dir string, name string) ([]ignoreRule, error) {
+	if !w.a.config.UsesGitignore() {
+		return rules, nil
+	}
+	content, err := fs.ReadFile(w.a.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	parsed := parseIgnoreFile(dir, content)
+	if len(parsed) == 0 {
+		return rules, nil
+	}
+	// Sibling directories share the rules of their parent, so they must not
+	// append to the same array
+	return append(rules[:len(rules):len(rules)], parsed...), nil
+}
+
+// ignoreRule is a pattern of an ignore file
+type ignoreRule struct {
+	// dir is the directory of the ignore file
+	dir string
+	re  *regexp.Regexp
+	// negate re-includes what an earlier pattern ignores, as in "!keep.tsx"
+	negate bool
+	// dirOnly matches directories only, as in "build/"
+	dirOnly bool
+}
+
+// parseIgnoreFile parses the patterns of a .gitignore file in dir. Patterns
+// with a slash other than a trailing one are relative to dir; the others
+// match at any depth below it.
+func parseIgnoreFile(dir string, content []byte) []ignoreRule {
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		// Trailing spaces are ignored unless escaped
+		for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+			line = line[:len(line)-1]
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{dir: dir}
+		if strings.HasPrefix(line, "!") {
+			rule.negate, line = true, line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly, line = true, strings.TrimSuffix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = "**/" + line
+		}
+		rule.re = compileGlob(line)
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ignored reports whether rules ignore the file or directory name. The last
+// matching rule decides, so later and deeper rules override earlier ones.
+func ignored(rules []ignoreRule, name string, isDir bool) bool {
+	result := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		relative := name
+		if rule.dir != "." {
+			relative = strings.TrimPrefix(name, rule.dir+"/")
+		}
+		if rule.re.MatchString(relative) {
+			result = !rule.negate
+		}
+	}
+	return result
+}
@@ -0,0 +1,177 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// sourceFiles returns the source files found in fsys with config and opts
+func sourceFiles(t *testing.T, fsys fstest.MapFS, config *Config, opts ...Option) []string {
+	t.Helper()
+	a, err := New(append([]Option{WithFS(fsys), WithConfig(config)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := a.SourceFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestFindFilesOrder(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/a/b.tsx": {},
+		"src/a-c.tsx": {},
+		"src/z.tsx":   {},
+	}
+	// Walks list src/a/b.tsx first
+	want := []string{"src/a-c.tsx", "src/a/b.tsx", "src/z.tsx"}
+	if got := sourceFiles(t, fsys, DefaultConfig()); !reflect.DeepEqual(got, want) {
+		t.Errorf("walked files = %v, want %v", got, want)
+	}
+	if got := sourceFiles(t, fsys, DefaultConfig(), WithFiles("src/z.tsx", "src/a/b.tsx", "src/a-c.tsx")); !reflect.DeepEqual(got, want) {
+		t.Errorf("given files = %v, want %v", got, want)
+	}
+}
+
+func TestIgnoreFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":              {Data: []byte("# generated code\n*.gen.tsx\n!keep.gen.tsx\nout/\n/root-only.tsx\n")},
+		".git/info/exclude":       {Data: []byte("local/\n")},
+		"page.tsx":                {},
+		"root-only.tsx":           {},
+		"a.gen.tsx":               {},
+		"keep.gen.tsx":            {},
+		"out/page.tsx":            {},
+		"local/page.tsx":          {},
+		"src/root-only.tsx":       {},
+		"src/b.gen.tsx":           {},
+		"src/.gitignore":          {Data: []byte("!b.gen.tsx\nlegacy\n")},
+		"src/legacy/page.tsx":     {},
+		"legacy/page.tsx":         {},
+		"src/nested/keep.gen.tsx": {},
+	}
+	tests := []struct {
+		name   string
+		config *Config
+		want   []string
+	}{
+		{
+			name:   "ignore files",
+			config: DefaultConfig(),
+			want: []string{
+				// Re-included by a negation of the same file and of a
+				// nested ignore file
+				"keep.gen.tsx",
+				// The nested .gitignore only applies below src
+				"legacy/page.tsx",
+				"page.tsx",
+				"src/b.gen.tsx",
+				"src/nested/keep.gen.tsx",
+				// A leading slash anchors a pattern to its directory
+				"src/root-only.tsx",
+			},
+		},
+		{
+			name:   "gitignore disabled",
+			config: &Config{Gitignore: new(bool)},
+			want: []string{
+				"a.gen.tsx", "keep.gen.tsx", "legacy/page.tsx", "local/page.tsx", "out/page.tsx", "page.tsx",
+				"root-only.tsx", "src/b.gen.tsx", "src/legacy/page.tsx", "src/nested/keep.gen.tsx", "src/root-only.tsx",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sourceFiles(t, fsys, tt.config); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		content string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"file pattern", ".", "*.log", "a/b.log", false, true},
+		{"directory only, on a directory", ".", "build/", "src/build", true, true},
+		{"directory only, on a file", ".", "build/", "src/build", false, false},
+		{"negation", ".", "*.log\n!keep.log", "keep.log", false, false},
+		{"later rules win", ".", "!keep.log\n*.log", "keep.log", false, true},
+		{"anchored", ".", "/a.log", "src/a.log", false, false},
+		{"pattern with a slash", ".", "src/*.log", "src/a.log", false, true},
+		{"relative to the ignore file", "src", "/a.log", "src/a.log", false, true},
+		{"escaped", ".", `\#notes`, "#notes", false, true},
+		{"comment", ".", "#notes", "#notes", false, false},
+	}
+	for _, tt := range tests {
+		if got := ignored(parseIgnoreFile(tt.dir, []byte(tt.content)), tt.path, tt.isDir); got != tt.want {
+			t.Errorf("%s: ignored(%q) = %v, want %v", tt.name, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFollowSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	for _, name := range []string{"src/page.tsx", "shared/button.tsx"} {
+		write(t, filepath.Join(root, filepath.FromSlash(name)))
+	}
+	write(t, filepath.Join(outside, "external.tsx"))
+	links := map[string]string{
+		// A cycle back to the project root
+		"src/loop": "..",
+		// A directory outside the project
+		"src/external": outside,
+		// A directory and a file found without following links
+		"src/shared":    filepath.Join("..", "shared"),
+		"src/alias.tsx": "page.tsx",
+		"src/broken":    "missing",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Skipf("symbolic links are not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		follow bool
+		want   []string
+	}{
+		{false, []string{"shared/button.tsx", "src/page.tsx"}},
+		{true, []string{"shared/button.tsx", "src/external/external.tsx", "src/page.tsx"}},
+	}
+	for _, tt := range tests {
+		a, err := New(WithFS(DirFS(root)), WithConfig(&Config{FollowSymlinks: tt.follow}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := a.SourceFiles()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("following links %v: files = %v, want %v", tt.follow, got, tt.want)
+		}
+	}
+}
+
+func write(t *testing.T, file string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,64 @@
+// Package analyzer finds unused and undeclared next-intl translation keys
+// and hardcoded user-facing strings in a Next.js project.
+//
+// # Usage
+//
+// Create an Analyzer with New and run it with Analyze:
+//
+//	a, err := analyzer.New(
+//		analyzer.WithDir("path/to/project"),
+//		analyzer.WithConfig(config),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	results, err := a.Analyze(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	for _, issue := range results.Issues() {
+//		fmt.Printf("%s:%d: [%s] %s\n", issue.File, issue.Line, issue.Rule, issue.Key)
+//	}
+//	for _, diagnostic := range results.Diagnostics {
+//		log.Println(diagnostic)
+//	}
+//
+// The project can be any fs.FS, which keeps tests free of temporary
+// directories:
+//
+//	fsys := fstest.MapFS{
+//		"messages/en.json": {Data: []byte(`{"Home": {"title": "Welcome"}}`)},
+//		"app/page.tsx":     {Data: []byte("const t = useTranslations('Home');\nt('title')\n")},
+//	}
+//	a, err := analyzer.New(analyzer.WithFS(fsys))
+//
+// WithFiles limits the analysis to some files of the project, for example
+// the files changed in a commit.
+//
+// # Side effects
+//
+// The package does not print. Problems that do not stop the analysis, such
+// as a message file with invalid JSON, are returned in
+// AnalysisResult.Diagnostics. Files are only read through the fs.FS of the
+// analyzer; the only writes are those of a ParseCache stored on disk and of
+// the explicit Save methods, such as Baseline.Save. Analyze stops when its
+// context is cancelled and returns the context's error.
+//
+// # Compatibility
+//
+// Within a major version:
+//
+//   - Exported identifiers are not removed or renamed, and function
+//     signatures do not change. New options, fields and methods may be
+//     added, so construct structs with field names.
+//   - Rule IDs, the Directive constants, the TranslationType values and the
+//     configuration file format stay valid.
+//   - The order of AnalysisResult.Issues and of every result list is
+//     deterministic and does not depend on concurrency.
+//   - Which hardcoded strings are detected, their confidence and their
+//     signals may change between releases, as the heuristics improve. The
+//     parse cache is keyed with Version, so it is never reused across
+//     releases.
+//   - The messages of diagnostics and errors are meant for humans and may
+//     change.
+package analyzer
@@ -0,0 +1,355 @@
+package analyzer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The analyzer reads every project file through an fs.FS, see WithFS. The
+// adapters below cover a directory on disk, a zip or tar archive and an
+// overlay of unsaved editor buffers on top of another file system.
+
+// DirFS returns the file system of the directory dir. An empty dir is the
+// working directory.
+func DirFS(dir string) fs.FS {
+	if dir == "" {
+		dir = "."
+	}
+	return os.DirFS(dir)
+}
+
+// OpenArchive reads the zip or tar archive at path, optionally compressed
+// with gzip, into memory. When every file of the archive is inside a single
+// top-level directory, as in archives of git revisions, the returned file
+// system is rooted at that directory.
+func OpenArchive(path string) (fs.FS, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive %s: %w", path, err)
+	}
+
+	var fsys fs.FS
+	if bytes.HasPrefix(content, []byte("PK\x03\x04")) || bytes.HasPrefix(content, []byte("PK\x05\x06")) {
+		fsys, err = zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	} else {
+		fsys, err = TarFS(bytes.NewReader(content))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive %s: %w", path, err)
+	}
+	return archiveRoot(fsys)
+}
+
+// archiveRoot descends into the only entry of fsys when it is a directory
+func archiveRoot(fsys fs.FS) (fs.FS, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return fs.Sub(fsys, entries[0].Name())
+	}
+	return fsys, nil
+}
+
+// TarFS reads a tar archive, optionally compressed with gzip, into memory.
+// Only regular files are kept; links and entries with paths leaving the
+// archive root are skipped.
+func TarFS(r io.Reader) (fs.FS, error) {
+	buffered := bufio.NewReader(r)
+	if magic, _ := buffered.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = buffered
+	}
+
+	fsys := newMemFS()
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return fsys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if !fs.ValidPath(name) || name == "." {
+			continue
+		}
+		content, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, err
+		}
+		fsys.set(name, content, header.ModTime)
+	}
+}
+
+// Overlay is a file system showing the unsaved content of files, such as the
+// buffers of an editor, on top of a base file system. It is safe for
+// concurrent use.
+type Overlay struct {
+	base fs.FS
+
+	mu    sync.RWMutex
+	files *memFS
+}
+
+// NewOverlay returns an overlay without files on top of base
+func NewOverlay(base fs.FS) *Overlay {
+	return &Overlay{base: base, files: newMemFS()}
+}
+
+// Set makes name read as content until it is removed with Delete. name is a
+// slash-separated path relative to the root of the file system.
+func (o *Overlay) Set(name string, content []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.files.set(name, content, time.Now())
+}
+
+// Delete removes the content set for name, which then reads from the base
+// file system again
+func (o *Overlay) Delete(name string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.files.delete(name)
+}
+
+// Open implements fs.FS
+func (o *Overlay) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	o.mu.RLock()
+	_, isFile := o.files.files[name]
+	_, isDir := o.files.dirs[name]
+	if isFile {
+		defer o.mu.RUnlock()
+		return o.files.Open(name)
+	}
+	o.mu.RUnlock()
+
+	if !isDir {
+		file, err := o.base.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		info, err := file.Stat()
+		if err != nil || !info.IsDir() {
+			return file, err
+		}
+		file.Close()
+	}
+
+	// Directories list the entries of both file systems
+	entries, err := o.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	return &memDir{info: memInfo{name: path.Base(name), mode: fs.ModeDir | 0555}, entries: entries}, nil
+}
+
+// ReadFile implements fs.ReadFileFS
+func (o *Overlay) ReadFile(name string) ([]byte, error) {
+	o.mu.RLock()
+	if file, ok := o.files.files[name]; ok {
+		defer o.mu.RUnlock()
+		return append([]byte{}, file.content...), nil
+	}
+	o.mu.RUnlock()
+	return fs.ReadFile(o.base, name)
+}
+
+// ReadDir implements fs.ReadDirFS. Files of the overlay replace files of the
+// base file system with the same name.
+func (o *Overlay) ReadDir(name string) ([]fs.DirEntry, error) {
+	o.mu.RLock()
+	overlaid, inOverlay := o.files.readDir(name)
+	o.mu.RUnlock()
+
+	entries, err := fs.ReadDir(o.base, name)
+	if err != nil && !(inOverlay && errors.Is(err, fs.ErrNotExist)) {
+		return nil, err
+	}
+
+	byName := make(map[string]fs.DirEntry, len(entries)+len(overlaid))
+	for _, entry := range entries {
+		byName[entry.Name()] = entry
+	}
+	for _, entry := range overlaid {
+		if existing, ok := byName[entry.Name()]; ok && existing.IsDir() && entry.IsDir() {
+			continue
+		}
+		byName[entry.Name()] = entry
+	}
+	return sortedEntries(byName), nil
+}
+
+// memFS is a read-only in-memory file system. Directories are implied by the
+// paths of the files.
+type memFS struct {
+	files map[string]*memFile
+	// dirs maps every directory to the names of its entries
+	dirs map[string]map[string]bool
+}
+
+type memFile struct {
+	content []byte
+	modTime time.Time
+}
+
+func newMemFS() *memFS {
+	return &memFS{files: make(map[string]*memFile), dirs: map[string]map[string]bool{".": {}}}
+}
+
+func (m *memFS) set(name string, content []byte, modTime time.Time) {
+	m.files[name] = &memFile{content: content, modTime: modTime}
+	for dir, entry := path.Dir(name), path.Base(name); ; dir, entry = path.Dir(dir), path.Base(dir) {
+		if m.dirs[dir] == nil {
+			m.dirs[dir] = make(map[string]bool)
+		}
+		m.dirs[dir][entry] = true
+		if dir == "." {
+			return
+		}
+	}
+}
+
+func (m *memFS) delete(name string) {
+	if _, ok := m.files[name]; !ok {
+		return
+	}
+	files := m.files
+	*m = *newMemFS()
+	for other, file := range files {
+		if other != name {
+			m.set(other, file.content, file.modTime)
+		}
+	}
+}
+
+// readDir returns the entries of the directory name and whether it exists
+func (m *memFS) readDir(name string) ([]fs.DirEntry, bool) {
+	names, ok := m.dirs[name]
+	if !ok {
+		return nil, false
+	}
+	byName := make(map[string]fs.DirEntry, len(names))
+	for entry := range names {
+		byName[entry] = fs.FileInfoToDirEntry(m.stat(path.Join(name, entry)))
+	}
+	return sortedEntries(byName), true
+}
+
+func (m *memFS) stat(name string) memInfo {
+	if file, ok := m.files[name]; ok {
+		return memInfo{name: path.Base(name), size: int64(len(file.content)), mode: 0444, modTime: file.modTime}
+	}
+	return memInfo{name: path.Base(name), mode: fs.ModeDir | 0555}
+}
+
+func (m *memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if file, ok := m.files[name]; ok {
+		return &memReader{Reader: bytes.NewReader(file.content), info: m.stat(name)}, nil
+	}
+	if entries, ok := m.readDir(name); ok {
+		return &memDir{info: m.stat(name), entries: entries}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (m *memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, ok := m.readDir(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	return entries, nil
+}
+
+func sortedEntries(byName map[string]fs.DirEntry) []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(byName))
+	for _, entry := range byName {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries
+}
+
+type memInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) Mode() fs.FileMode  { return i.mode }
+func (i memInfo) ModTime() time.Time { return i.modTime }
+func (i memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memInfo) Sys() interface{}   { return nil }
+
+// memReader is an open file of a memFS
+type memReader struct {
+	*bytes.Reader
+	info memInfo
+}
+
+func (f *memReader) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memReader) Close() error               { return nil }
+
+// memDir is an open directory listing fixed entries
+type memDir struct {
+	info    memInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+func (d *memDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(rest) {
+		count = len(rest)
+	}
+	d.offset += count
+	return rest[:count], nil
+}
@@ -0,0 +1,299 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Git support reads project files from a git repository by running the git
+// command, so that staged content and other revisions can be analyzed
+// without checking them out.
+
+// GitRevisionFS returns the project directory dir as of the git revision
+// rev, such as a branch, a tag or a commit. Only the files the analysis reads
+// are loaded into memory.
+func GitRevisionFS(ctx context.Context, dir string, rev string) (fs.FS, error) {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return nil, fmt.Errorf("invalid git revision %q", rev)
+	}
+
+	// Paths are relative to dir, and only files below dir are listed
+	listing, err := runGit(ctx, dir, nil, "ls-tree", "-r", "-z", rev)
+	if err != nil {
+		return nil, err
+	}
+	var names, objects []string
+	for _, record := range splitNull(listing) {
+		// <mode> <type> <object>\t<path>
+		fields := strings.SplitN(record, "\t", 2)
+		meta := strings.Fields(fields[0])
+		if len(fields) != 2 || len(meta) != 3 || meta[1] != "blob" || !isGitFile(meta[0]) || !isProjectFile(fields[1]) {
+			continue
+		}
+		names = append(names, fields[1])
+		objects = append(objects, meta[2])
+	}
+	return readGitFiles(ctx, dir, names, objects)
+}
+
+// StagedChanges describes the changes staged in the git index of a project
+type StagedChanges struct {
+	// FS holds the project files as staged
+	FS fs.FS
+	// Files lists the staged files relative to the project root, with
+	// forward slashes. Deleted files are included.
+	Files []string
+	// Keys lists by locale the message keys that the staged message files
+	// add, remove or change
+	Keys map[string]map[string]bool
+}
+
+// LoadStagedChanges reads the git index of the repository containing the
+// project directory dir
+func LoadStagedChanges(ctx context.Context, dir string) (*StagedChanges, error) {
+	prefix, err := runGit(ctx, dir, nil, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	repoPrefix := strings.TrimSpace(string(prefix))
+
+	// Every project file as staged, paths relative to dir
+	listing, err := runGit(ctx, dir, nil, "ls-files", "--stage", "-z", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	var names, objects []string
+	for _, record := range splitNull(listing) {
+		// <mode> <object> <stage>\t<path>
+		fields := strings.SplitN(record, "\t", 2)
+		meta := strings.Fields(fields[0])
+		if len(fields) != 2 || len(meta) != 3 || meta[2] != "0" || !isGitFile(meta[0]) || !isProjectFile(fields[1]) {
+			continue
+		}
+		names = append(names, fields[1])
+		objects = append(objects, meta[1])
+	}
+	files, err := readGitFiles(ctx, dir, names, objects)
+	if err != nil {
+		return nil, err
+	}
+	staged := &StagedChanges{FS: files, Keys: make(map[string]map[string]bool)}
+
+	// --no-renames reports a renamed file as deleted and added, so that the
+	// keys of both paths count as touched
+	diff, err := runGit(ctx, dir, nil, "diff", "--cached", "--name-only", "--no-renames", "--relative", "-z")
+	if err != nil {
+		return nil, err
+	}
+	staged.Files = splitNull(diff)
+
+	var messageFiles, headObjects []string
+	for _, name := range staged.Files {
+		if isTranslationFile(name) {
+			messageFiles = append(messageFiles, name)
+			headObjects = append(headObjects, "HEAD:"+repoPrefix+name)
+		}
+	}
+	headBlobs, err := readGitObjects(ctx, dir, headObjects)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := NewTranslationParser()
+	for i, name := range messageFiles {
+		before := parseMessageKeys(parser, name, headBlobs[i])
+		after := map[string]Translation{}
+		if content, err := fs.ReadFile(staged.FS, name); err == nil {
+			after = parseMessageKeys(parser, name, content)
+		}
+
+		locale := LocaleFromPath(name)
+		if staged.Keys[locale] == nil {
+			staged.Keys[locale] = make(map[string]bool)
+		}
+		for key, translation := range after {
+			if previous, ok := before[key]; !ok || previous.Value != translation.Value {
+				staged.Keys[locale][key] = true
+			}
+		}
+		for key := range before {
+			if _, ok := after[key]; !ok {
+				staged.Keys[locale][key] = true
+			}
+		}
+	}
+
+	return staged, nil
+}
+
+// parseMessageKeys returns the keys of a message file, or none when content
+// is missing or invalid
+func parseMessageKeys(parser *TranslationParser, name string, content []byte) map[string]Translation {
+	if content == nil {
+		return map[string]Translation{}
+	}
+	declared, err := parser.ParseTranslation(name, content)
+	if err != nil {
+		return map[string]Translation{}
+	}
+	return declared
+}
+
+// Apply removes from results the issues that the staged changes do not
+// touch: issues are kept when they are located in a staged source file, or
+// concern a key that a staged message file adds, removes or changes in the
+// issue's locale. Results are modified in place.
+func (c *StagedChanges) Apply(results *AnalysisResult, projectPath string) {
+	staged := make(map[string]bool, len(c.Files))
+	for _, file := range c.Files {
+		staged[file] = true
+	}
+	inStagedFile := func(translation Translation) bool {
+		return staged[relativePath(projectPath, translation.File)]
+	}
+	touchesKey := func(translation Translation) bool {
+		return c.Keys[translation.Locale][translation.Key]
+	}
+	touchesKeyInAnyLocale := func(translation Translation) bool {
+		for _, keys := range c.Keys {
+			if keys[translation.Key] {
+				return true
+			}
+		}
+		return false
+	}
+
+	results.UnusedTranslations = removeTranslations(results.UnusedTranslations, func(translation Translation) bool {
+		return !touchesKey(translation)
+	})
+	results.UndeclaredTranslations = removeTranslations(results.UndeclaredTranslations, func(translation Translation) bool {
+		return !inStagedFile(translation) && !touchesKey(translation)
+	})
+	results.HardcodedStrings = removeTranslations(results.HardcodedStrings, func(translation Translation) bool {
+		return !inStagedFile(translation)
+	})
+	// A key missing in a locale is touched by a change of any locale
+	results.MissingTranslations = removeTranslations(results.MissingTranslations, func(translation Translation) bool {
+		return !touchesKeyInAnyLocale(translation)
+	})
+	results.UnusedSuppressions = removeTranslations(results.UnusedSuppressions, func(translation Translation) bool {
+		return !inStagedFile(translation)
+	})
+
+	// Keep the per-locale lists consistent with the overall lists
+	for locale, localeResult := range results.LocaleResults {
+		inLocale := func(translation Translation) bool { return translation.Locale != locale }
+		localeResult.UnusedTranslations = removeTranslations(results.UnusedTranslations, inLocale)
+		localeResult.UndeclaredTranslations = removeTranslations(results.UndeclaredTranslations, inLocale)
+	}
+}
+
+// isGitFile reports whether a git file mode is a regular file, as opposed to
+// a symbolic link or a submodule
+func isGitFile(mode string) bool {
+	return mode == "100644" || mode == "100755"
+}
+
+// isProjectFile reports whether the analysis may read name: source files,
+// scripts referencing key constants, message files, the configuration file
+// and key manifests, which may be any JSON file
+func isProjectFile(name string) bool {
+	return IsSourceFile(name) || isScriptFile(name) || path.Ext(name) == ".json" || path.Base(name) == ConfigFileName
+}
+
+// readGitFiles returns an in-memory file system holding the given objects
+// under the given names
+func readGitFiles(ctx context.Context, dir string, names []string, objects []string) (fs.FS, error) {
+	blobs, err := readGitObjects(ctx, dir, objects)
+	if err != nil {
+		return nil, err
+	}
+
+	files := newMemFS()
+	for i, name := range names {
+		if blobs[i] != nil {
+			files.set(name, blobs[i], time.Time{})
+		}
+	}
+	return files, nil
+}
+
+// readGitObjects returns the content of the given objects, such as blob IDs
+// or HEAD:<path> names, with a single git process. Missing objects are nil.
+func readGitObjects(ctx context.Context, dir string, objects []string) ([][]byte, error) {
+	contents := make([][]byte, len(objects))
+	if len(objects) == 0 {
+		return contents, nil
+	}
+
+	output, err := runGit(ctx, dir, []byte(strings.Join(objects, "\n")+"\n"), "cat-file", "--batch")
+	if err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(output))
+	for i, object := range objects {
+		// <object> <type> <size>\n<content>\n, or <object> missing\n
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("git cat-file: unexpected end of output at %s", object)
+		}
+		if strings.HasSuffix(header, " missing\n") || strings.HasSuffix(header, " ambiguous\n") {
+			continue
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("git cat-file: invalid header %q", strings.TrimSpace(header))
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("git cat-file: invalid header %q", strings.TrimSpace(header))
+		}
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(reader, content); err != nil {
+			return nil, fmt.Errorf("git cat-file: truncated content of %s", object)
+		}
+		if fields[1] == "blob" {
+			contents[i] = content[:size]
+		}
+	}
+	return contents, nil
+}
+
+// runGit runs git in dir, feeding it stdin, and returns its output
+func runGit(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// splitNull splits the NUL-terminated records of git's -z output
+func splitNull(output []byte) []string {
+	var records []string
+	for _, record := range strings.Split(string(output), "\x00") {
+		if record != "" {
+			records = append(records, record)
+		}
+	}
+	return records
+}
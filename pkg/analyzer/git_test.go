@@ -0,0 +1,186 @@
+package analyzer
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// gitRepo creates a git repository holding files in a single commit
+func gitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	git(t, dir, "init", "-q")
+	writeFiles(t, dir, files)
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	// The commits of the test must not depend on the user's configuration
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL="+os.DevNull, "GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadStagedChanges(t *testing.T) {
+	for _, project := range []string{"", "apps/web"} {
+		name := project
+		if name == "" {
+			name = "repository root"
+		}
+		t.Run(name, func(t *testing.T) {
+			files := map[string]string{
+				"messages/en.json": `{"Common": {"save": "Save", "cancel": "Cancel", "close": "Close"}}`,
+				"messages/de.json": `{"Common": {"save": "Speichern"}}`,
+				"src/page.tsx":     `export const Page = () => <p>Page</p>;`,
+				"src/old.tsx":      `export const Old = () => <p>Old</p>;`,
+				"src/other.tsx":    `export const Other = () => <p>Other</p>;`,
+			}
+			prefixed := make(map[string]string, len(files))
+			for file, content := range files {
+				prefixed[filepath.ToSlash(filepath.Join(project, file))] = content
+			}
+			// A file of another project is out of reach
+			prefixed["README.md"] = "readme"
+			repo := gitRepo(t, prefixed)
+			dir := filepath.Join(repo, filepath.FromSlash(project))
+
+			// A staged edit of a message file and of a source file, a staged
+			// delete, and an edit that is not staged
+			writeFiles(t, dir, map[string]string{
+				"messages/en.json": `{"Common": {"save": "Save changes", "cancel": "Cancel", "open": "Open"}}`,
+				"messages/fr.json": `{"Common": {"save": "Enregistrer"}}`,
+				"src/page.tsx":     `export const Page = () => <p>Staged</p>;`,
+			})
+			git(t, dir, "rm", "-q", "src/old.tsx")
+			git(t, dir, "add", "messages", "src/page.tsx")
+			writeFiles(t, dir, map[string]string{
+				"src/page.tsx":  `export const Page = () => <p>Not staged</p>;`,
+				"src/other.tsx": `export const Other = () => <p>Not staged</p>;`,
+			})
+
+			staged, err := LoadStagedChanges(context.Background(), dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Paths are relative to the project
+			wantFiles := []string{"messages/en.json", "messages/fr.json", "src/old.tsx", "src/page.tsx"}
+			if !reflect.DeepEqual(staged.Files, wantFiles) {
+				t.Errorf("files = %v, want %v", staged.Files, wantFiles)
+			}
+			wantKeys := map[string]map[string]bool{
+				"en": {"Common.save": true, "Common.close": true, "Common.open": true},
+				"fr": {"Common": true, "Common.save": true},
+			}
+			if !reflect.DeepEqual(staged.Keys, wantKeys) {
+				t.Errorf("keys = %v, want %v", staged.Keys, wantKeys)
+			}
+
+			// The file system holds the staged content only
+			contents := map[string]string{
+				"src/page.tsx":  `export const Page = () => <p>Staged</p>;`,
+				"src/other.tsx": files["src/other.tsx"],
+			}
+			for name, want := range contents {
+				if content, err := fs.ReadFile(staged.FS, name); err != nil || string(content) != want {
+					t.Errorf("%s = %q, %v, want %q", name, content, err, want)
+				}
+			}
+			for _, name := range []string{"src/old.tsx", "README.md", "../../README.md"} {
+				if _, err := fs.Stat(staged.FS, name); err == nil {
+					t.Errorf("%s is in the staged files", name)
+				}
+			}
+		})
+	}
+}
+
+func TestReadGitObjects(t *testing.T) {
+	dir := gitRepo(t, map[string]string{"messages/en.json": `{"a": "A"}`})
+	contents, err := readGitObjects(context.Background(), dir, []string{"HEAD:messages/en.json", "HEAD:missing.json", "HEAD:messages"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Missing objects and trees are nil
+	want := [][]byte{[]byte(`{"a": "A"}`), nil, nil}
+	if !reflect.DeepEqual(contents, want) {
+		t.Errorf("contents = %q, want %q", contents, want)
+	}
+}
+
+func TestStagedChangesApply(t *testing.T) {
+	staged := &StagedChanges{
+		Files: []string{"messages/en.json", "src/page.tsx"},
+		Keys:  map[string]map[string]bool{"en": {"Common.save": true}},
+	}
+	results := newAnalysisResult()
+	results.UnusedTranslations = []Translation{
+		{Key: "Common.save", Locale: "en", File: "app/messages/en.json"},
+		{Key: "Common.save", Locale: "de", File: "app/messages/de.json"},
+		{Key: "Common.cancel", Locale: "en", File: "app/messages/en.json"},
+	}
+	results.UndeclaredTranslations = []Translation{
+		{Key: "Common.missing", Locale: "en", File: "app/src/page.tsx"},
+		{Key: "Common.missing", Locale: "en", File: "app/src/other.tsx"},
+		{Key: "Common.save", Locale: "en", File: "app/src/other.tsx"},
+	}
+	results.HardcodedStrings = []Translation{
+		{Key: "Staged", File: "app/src/page.tsx"},
+		{Key: "Other", File: "app/src/other.tsx"},
+	}
+	results.MissingTranslations = []Translation{
+		{Key: "Common.save", Locale: "de", File: "app/messages/en.json"},
+		{Key: "Common.cancel", Locale: "de", File: "app/messages/en.json"},
+	}
+	results.LocaleResults["en"] = &LocaleAnalysisResult{Locale: "en"}
+	staged.Apply(results, "app")
+
+	var got []string
+	for _, issue := range results.Issues() {
+		got = append(got, issue.Rule+" "+issue.Locale+" "+issue.File+" "+issue.Key)
+	}
+	// Issues of staged files and of keys staged in the issue's locale are
+	// kept; a key missing in a locale is touched by any locale
+	want := []string{
+		"hardcoded-string  app/src/page.tsx Staged",
+		"missing-translation de app/messages/en.json Common.save",
+		"undeclared-key en app/src/other.tsx Common.save",
+		"undeclared-key en app/src/page.tsx Common.missing",
+		"unused-key en app/messages/en.json Common.save",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues:\n%q\nwant:\n%q", got, want)
+	}
+	if en := results.LocaleResults["en"]; len(en.UnusedTranslations) != 1 || len(en.UndeclaredTranslations) != 2 {
+		t.Errorf("en results = %+v, want the issues of en", en)
+	}
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	globCacheMu sync.Mutex
+	globCache   = make(map[string]*regexp.Regexp)
+)
+
+// MatchGlob reports whether the slash-separated path name matches pattern.
+// Besides the path.Match syntax, patterns support "**" to match any number
+// of directories and "{a,b}" alternatives. A pattern without a slash is
+// matched against every path suffix, so "*.test.tsx" matches files in any
+// directory and "legacy" matches everything below a legacy directory.
+func MatchGlob(pattern string, name string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	name = strings.TrimPrefix(name, "./")
+
+	if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		pattern = "**/" + pattern
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	// "dir/" names a directory, which matches everything below it
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	re := compileGlob(pattern)
+	if re.MatchString(name) {
+		return true
+	}
+	// A pattern matching a directory matches everything below it
+	for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if re.MatchString(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileGlob(pattern string) *regexp.Regexp {
+	globCacheMu.Lock()
+	defer globCacheMu.Unlock()
+
+	if re, ok := globCache[pattern]; ok {
+		return re
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	inAlternatives := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		case c == '{':
+			inAlternatives = true
+			b.WriteString("(?:")
+		case c == '}' && inAlternatives:
+			inAlternatives = false
+			b.WriteString(")")
+		case c == ',' && inAlternatives:
+			b.WriteString("|")
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("/?$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		// Fall back to a literal match
+		re = regexp.MustCompile("^" + regexp.QuoteMeta(pattern) + "$")
+	}
+	globCache[pattern] = re
+	return re
+}
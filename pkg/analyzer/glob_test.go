@@ -0,0 +1,60 @@
+package analyzer
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"src/**", "src/a/b.tsx", true},
+		{"src/**", "srcx/a.tsx", false},
+		{"src/**/*.tsx", "src/a.tsx", true},
+		{"src/**/*.tsx", "src/a/b/c.tsx", true},
+		{"src/**/*.tsx", "src/a/b/c.ts", false},
+		{"**/*.stories.tsx", "src/ui/button.stories.tsx", true},
+		{"**/*.stories.tsx", "button.stories.tsx", true},
+		{"**", "any/file.tsx", true},
+		// Without a slash, a pattern matches any path suffix
+		{"*.test.tsx", "src/page.test.tsx", true},
+		{"*.test.tsx", "src/page.tsx", false},
+		// Patterns matching a directory match everything below it
+		{"legacy", "src/legacy/page.tsx", true},
+		{"legacy", "src/legacy-page.tsx", false},
+		{"src/legacy", "src/legacy/deep/page.tsx", true},
+		{"src/legacy/", "src/legacy/page.tsx", true},
+		{"src/{app,pages}/*.tsx", "src/app/page.tsx", true},
+		{"src/{app,pages}/*.tsx", "src/pages/index.tsx", true},
+		{"src/{app,pages}/*.tsx", "src/lib/util.tsx", false},
+		{"*.{ts,tsx}", "src/page.tsx", true},
+		{"*.{ts,tsx}", "src/page.jsx", false},
+		// * and ? do not cross directories
+		{"src/*.tsx", "src/a/b.tsx", false},
+		{"src/?.tsx", "src/a.tsx", true},
+		{"src/?.tsx", "src/ab.tsx", false},
+		{"src/[ab].tsx", "src/b.tsx", true},
+		{"src/[!ab].tsx", "src/a.tsx", false},
+		{"./src/*.tsx", "src/page.tsx", true},
+		{"/src/*.tsx", "./src/page.tsx", true},
+		{"/src/*.tsx", "app/src/page.tsx", false},
+		{`src/\*.tsx`, "src/*.tsx", true},
+		{`src/\*.tsx`, "src/a.tsx", false},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchGlobCache(t *testing.T) {
+	MatchGlob("cached/**/*.tsx", "cached/page.tsx")
+	re := compileGlob("cached/**/*.tsx")
+	if compileGlob("cached/**/*.tsx") != re {
+		t.Error("a pattern is compiled again")
+	}
+	if !MatchGlob("cached/**/*.tsx", "cached/a/page.tsx") || MatchGlob("cached/**/*.tsx", "other/page.tsx") {
+		t.Error("the cached pattern matches other paths")
+	}
+}
@@ -0,0 +1,185 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// stringLiteral matches a single-quoted, double-quoted or template string
+// literal, including its quotes
+const stringLiteral = `'(?:[^'\\\n]|\\.)*'|"(?:[^"\\\n]|\\.)*"|` + "`(?:[^`\\\\]|\\\\.)*`"
+
+// Patterns for string literals in places where they are displayed to the
+// user, complementing the tag based hardcodedPatterns.
+var (
+	// Example: <p>{'Save changes'}</p>, <p>{`Hello ${name}`}</p>
+	jsxExpressionPattern = regexp.MustCompile(`(?:^|[^=\w$])\{\s*(` + stringLiteral + `)\s*\}`)
+
+	// Example: <TextField label="Email" />, <Tooltip title={'Copy link'} />
+	propPattern = regexp.MustCompile(`(?:^|[\s<{(,])([A-Za-z][\w-]*)=(?:('[^'\n]*'|"[^"\n]*")|\{\s*(` + stringLiteral + `)\s*\})`)
+
+	// Example: [{ label: 'Monthly', value: 'monthly' }]
+	objectPropertyPattern = regexp.MustCompile(`(?:^|[\s{,(])['"]?([A-Za-z_$][\w$-]*)['"]?\s*:\s*(` + stringLiteral + `)`)
+
+	// Example: toast.error('Something went wrong'). A "window." prefix of
+	// the callee is ignored.
+	callSitePattern = regexp.MustCompile(`((?:[A-Za-z_$][\w$]*\.)*[A-Za-z_$][\w$]*)\s*\(\s*(` + stringLiteral + `)`)
+
+	// Example: document.title = 'Settings'
+	documentTitlePattern = regexp.MustCompile(`\bdocument\.title\s*=\s*(` + stringLiteral + `)`)
+
+	// Example: export const metadata: Metadata = {
+	// Example: export async function generateMetadata({ params }) {
+	metadataStartPattern = regexp.MustCompile(`\bexport\s+(?:const\s+metadata\b|(?:async\s+)?function\s+generateMetadata\b)`)
+
+	templatePlaceholderPattern = regexp.MustCompile(`\$\{[^}]*\}`)
+)
+
+// metadataBlock tracks whether parsing is inside a Next.js metadata export
+// or generateMetadata function, whose fields end up in the page head
+type metadataBlock struct {
+	active bool
+	opened bool
+	braces int
+	parens int
+}
+
+// update consumes line and reports whether it is part of a metadata block.
+// Braces inside parentheses, such as destructured parameters, are ignored.
+func (m *metadataBlock) update(line string) bool {
+	if !m.active {
+		loc := metadataStartPattern.FindStringIndex(line)
+		if loc == nil {
+			return false
+		}
+		*m = metadataBlock{active: true}
+		line = line[loc[1]:]
+	}
+
+	for _, char := range line {
+		switch {
+		case char == '(':
+			m.parens++
+		case char == ')':
+			m.parens--
+		case char == '{' && m.parens == 0:
+			m.braces++
+			m.opened = true
+		case char == '}' && m.parens == 0:
+			m.braces--
+		}
+	}
+	if m.opened && m.braces <= 0 {
+		m.active = false
+	}
+	return true
+}
+
+// displayedLiterals returns the string literals of line, with their quotes,
+// that appear in a place where they are displayed to the user: JSX
+// expression containers, translatable props and object properties, UI call
+// sites, document.title and metadata fields.
+func (p *TranslationParser) displayedLiterals(line string, inMetadata bool) []string {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+		return nil
+	}
+
+	var literals []string
+	for _, match := range jsxExpressionPattern.FindAllStringSubmatch(line, -1) {
+		literals = append(literals, match[1])
+	}
+	for _, match := range propPattern.FindAllStringSubmatch(line, -1) {
+		if !p.translatableProps[match[1]] {
+			continue
+		}
+		if match[2] != "" {
+			literals = append(literals, match[2])
+		} else {
+			literals = append(literals, match[3])
+		}
+	}
+	for _, match := range objectPropertyPattern.FindAllStringSubmatch(line, -1) {
+		if p.translatableProps[match[1]] || (inMetadata && p.metadataFields[match[1]]) {
+			literals = append(literals, match[2])
+		}
+	}
+	for _, match := range callSitePattern.FindAllStringSubmatch(line, -1) {
+		if p.uiCallSites[strings.TrimPrefix(match[1], "window.")] {
+			literals = append(literals, match[2])
+		}
+	}
+	for _, match := range documentTitlePattern.FindAllStringSubmatch(line, -1) {
+		literals = append(literals, match[1])
+	}
+	return literals
+}
+
+// literalText returns the text of a string literal without its quotes.
+// Template literal placeholders are kept as written so that the text
+// identifies the literal in reports.
+func literalText(literal string) string {
+	if len(literal) < 2 {
+		return ""
+	}
+	quote := literal[:1]
+	text := literal[1 : len(literal)-1]
+	return strings.NewReplacer(`\`+quote, quote, `\\`, `\`).Replace(text)
+}
+
+// isDisplayedText reports whether a string literal found in a display
+// context is text rather than an identifier, a key, a URL or a number. The
+// context already says that the string is shown to the user, so the text is
+// only filtered here and then scored like any other candidate.
+func (p *TranslationParser) isDisplayedText(text string) bool {
+	text = strings.TrimSpace(templatePlaceholderPattern.ReplaceAllString(text, " "))
+	if utf8.RuneCountInString(text) < 2 || detectScript(text) == scriptNone {
+		return false
+	}
+	if p.isNumeric(text) {
+		return false
+	}
+
+	// URLs and paths
+	for _, prefix := range []string{"/", "./", "../", "#", "@/", "~/", "www.", "mailto:"} {
+		if strings.HasPrefix(text, prefix) {
+			return false
+		}
+	}
+	if strings.Contains(text, "://") {
+		return false
+	}
+
+	// Markup and code
+	if strings.ContainsAny(text, "{}<>=") {
+		return false
+	}
+
+	// A single ASCII word is text only when it is capitalized like a label
+	// ("Email", "OK"). Lowercase words, camelCase, PascalCase, snake_case,
+	// kebab-case and dotted words are identifiers, enum values or
+	// translation keys.
+	if !strings.ContainsAny(text, " \t") && isASCII(text) {
+		word := strings.TrimRight(text, ".:!?")
+		first, _ := utf8.DecodeRuneInString(word)
+		if !unicode.IsUpper(first) || strings.ContainsAny(word, "_.-/:") || hasInnerCapital(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasInnerCapital reports whether an uppercase letter follows a lowercase
+// one, as in PascalCase or camelCase identifiers
+func hasInnerCapital(word string) bool {
+	previousLower := false
+	for _, char := range word {
+		if unicode.IsUpper(char) && previousLower {
+			return true
+		}
+		previousLower = unicode.IsLower(char)
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package analyzer
+
+import "testing"
+
+func TestIsDisplayedText(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Save changes", true},
+		{"Email", true},
+		{"OK", true},
+		{"Hello ${name}", true},
+		{"Discard unsaved changes?", true},
+		{"Сохранить", true},
+		{"保存", true},
+		// Identifiers, keys and enum values
+		{"monthly", false},
+		{"arrowLeft", false},
+		{"ArrowLeft", false},
+		{"arrow-left", false},
+		{"user_name", false},
+		{"button.save", false},
+		// URLs, paths, markup and numbers
+		{"/settings", false},
+		{"./logo.png", false},
+		{"https://example.com", false},
+		{"mailto:support@example.com", false},
+		{"<b>bold</b>", false},
+		{"42", false},
+		{" ", false},
+		{"", false},
+	}
+	p := NewTranslationParser()
+	for _, tt := range tests {
+		if got := p.isDisplayedText(tt.text); got != tt.want {
+			t.Errorf("isDisplayedText(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestExpressionComponent(t *testing.T) {
+	// Metadata, option labels, UI calls, DOM assignments, expression
+	// children and text props are reported; translation calls, identifiers,
+	// URLs and class names are not
+	assertTexts(t, hardcodedTexts(t, "../../test-data/src/components/ExpressionComponent.tsx"), []string{
+		"Account settings",
+		"Manage your profile and notification preferences",
+		"Monthly",
+		"Yearly",
+		"Something went wrong",
+		"Settings",
+		"Discard unsaved changes?",
+		"Save changes",
+		"Hello ${name}",
+		"Email",
+		"We never share your email",
+	})
+}
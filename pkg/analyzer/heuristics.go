@@ -0,0 +1,265 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Heuristics is the data the hardcoded-string detection runs with. The lists
+// and constants of constants.go are the defaults, see DefaultHeuristics. A
+// project changes them with the "heuristics" section of its configuration
+// file, and a parser receives them with WithHeuristics.
+type Heuristics struct {
+	// ShortUIWords are short words reported despite their length
+	ShortUIWords []string `json:"shortUIWords"`
+	// TechnicalPatterns indicate code. Patterns made of letters only match
+	// whole words; other patterns match anywhere in the text.
+	TechnicalPatterns []string `json:"technicalPatterns"`
+	// UIPatterns indicate user-facing text, such as product terms
+	UIPatterns []string `json:"uiPatterns"`
+	// TranslatableProps are the props and object properties whose string
+	// values are displayed to the user
+	TranslatableProps []string `json:"translatableProps"`
+	// UICallSites are the functions whose string arguments are displayed
+	UICallSites []string `json:"uiCallSites"`
+	// MetadataFields are the fields of Next.js metadata exports that are
+	// displayed, in addition to TranslatableProps
+	MetadataFields []string `json:"metadataFields"`
+	// Allowlist lists brand and product names that never need translating.
+	// Text made only of allowlisted names is not reported.
+	Allowlist []string `json:"allowlist"`
+	// Thresholds are the length and ratio limits of the text heuristics
+	Thresholds Thresholds `json:"thresholds"`
+	// Weights maps signal names to the confidence they add
+	Weights map[string]float64 `json:"weights"`
+}
+
+// Thresholds are the numeric limits of the hardcoded-string heuristics.
+// Lengths are in characters.
+type Thresholds struct {
+	MinTextLength               int     `json:"minTextLength"`
+	MediumTextThreshold         int     `json:"mediumTextThreshold"`
+	LongTextThreshold           int     `json:"longTextThreshold"`
+	ShortTextRatio              float64 `json:"shortTextRatio"`
+	MediumTextRatio             float64 `json:"mediumTextRatio"`
+	LongTextRatio               float64 `json:"longTextRatio"`
+	MinWordsForSentence         int     `json:"minWordsForSentence"`
+	MinWordsForNonLatinSentence int     `json:"minWordsForNonLatinSentence"`
+	MinCJKTextLength            int     `json:"minCJKTextLength"`
+	CJKLongTextThreshold        int     `json:"cjkLongTextThreshold"`
+	CJKShortTextRatio           float64 `json:"cjkShortTextRatio"`
+	CJKLongTextRatio            float64 `json:"cjkLongTextRatio"`
+	BaseConfidence              float64 `json:"baseConfidence"`
+}
+
+// DefaultHeuristics returns the heuristics of constants.go. The returned
+// value shares no slices or maps with the package-level defaults.
+func DefaultHeuristics() Heuristics {
+	return Heuristics{
+		ShortUIWords:      copyStrings(ShortUIWords),
+		TechnicalPatterns: copyStrings(TechnicalPatterns),
+		UIPatterns:        copyStrings(UIPatterns),
+		TranslatableProps: copyStrings(TranslatableProps),
+		UICallSites:       copyStrings(UICallSites),
+		MetadataFields:    copyStrings(MetadataFields),
+		Allowlist:         []string{},
+		Thresholds: Thresholds{
+			MinTextLength:               MinTextLength,
+			MediumTextThreshold:         MediumTextThreshold,
+			LongTextThreshold:           LongTextThreshold,
+			ShortTextRatio:              ShortTextRatio,
+			MediumTextRatio:             MediumTextRatio,
+			LongTextRatio:               LongTextRatio,
+			MinWordsForSentence:         MinWordsForSentence,
+			MinWordsForNonLatinSentence: MinWordsForNonLatinSentence,
+			MinCJKTextLength:            MinCJKTextLength,
+			CJKLongTextThreshold:        CJKLongTextThreshold,
+			CJKShortTextRatio:           CJKShortTextRatio,
+			CJKLongTextRatio:            CJKLongTextRatio,
+			BaseConfidence:              BaseConfidence,
+		},
+		Weights: defaultWeights(),
+	}
+}
+
+func copyStrings(list []string) []string {
+	return append([]string{}, list...)
+}
+
+// ListSetting changes one of the heuristic lists. Replace, when set,
+// substitutes the default list; Merge then adds entries and Remove drops
+// them. A plain JSON array is read as {"merge": [...]}.
+type ListSetting struct {
+	Replace *[]string `json:"replace,omitempty"`
+	Merge   []string  `json:"merge,omitempty"`
+	Remove  []string  `json:"remove,omitempty"`
+}
+
+func (l *ListSetting) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		*l = ListSetting{}
+		return json.Unmarshal(data, &l.Merge)
+	}
+
+	type listSetting ListSetting
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var setting listSetting
+	if err := decoder.Decode(&setting); err != nil {
+		return err
+	}
+	*l = ListSetting(setting)
+	return nil
+}
+
+// apply returns list changed by the setting. A nil setting keeps list.
+func (l *ListSetting) apply(list []string) []string {
+	if l == nil {
+		return list
+	}
+	if l.Replace != nil {
+		list = copyStrings(*l.Replace)
+	}
+
+	removed := make(map[string]bool, len(l.Remove))
+	for _, entry := range l.Remove {
+		removed[entry] = true
+	}
+	result := make([]string, 0, len(list)+len(l.Merge))
+	seen := make(map[string]bool, len(list)+len(l.Merge))
+	for _, entry := range append(copyStrings(list), l.Merge...) {
+		if removed[entry] || seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		result = append(result, entry)
+	}
+	return result
+}
+
+// HeuristicsConfig is the "heuristics" section of the configuration file.
+//
+// Example:
+//
+//	"heuristics": {
+//	  "uiPatterns": ["Checkout", "Wishlist"],
+//	  "technicalPatterns": { "remove": ["delete"] },
+//	  "allowlist": ["Acme", "GitHub"],
+//	  "thresholds": { "minTextLength": 4 },
+//	  "weights": { "technical-pattern": -0.2 }
+//	}
+type HeuristicsConfig struct {
+	ShortUIWords      *ListSetting `json:"shortUIWords,omitempty"`
+	TechnicalPatterns *ListSetting `json:"technicalPatterns,omitempty"`
+	UIPatterns        *ListSetting `json:"uiPatterns,omitempty"`
+	TranslatableProps *ListSetting `json:"translatableProps,omitempty"`
+	UICallSites       *ListSetting `json:"uiCallSites,omitempty"`
+	MetadataFields    *ListSetting `json:"metadataFields,omitempty"`
+	Allowlist         *ListSetting `json:"allowlist,omitempty"`
+	// Thresholds overrides individual fields of Thresholds
+	Thresholds json.RawMessage `json:"thresholds,omitempty"`
+	// Weights overrides the weights of individual signals
+	Weights map[string]float64 `json:"weights,omitempty"`
+}
+
+// Apply returns base changed by the configuration
+func (c *HeuristicsConfig) Apply(base Heuristics) (Heuristics, error) {
+	if c == nil {
+		return base, nil
+	}
+
+	heuristics := base
+	heuristics.ShortUIWords = c.ShortUIWords.apply(base.ShortUIWords)
+	heuristics.TechnicalPatterns = c.TechnicalPatterns.apply(base.TechnicalPatterns)
+	heuristics.UIPatterns = c.UIPatterns.apply(base.UIPatterns)
+	heuristics.TranslatableProps = c.TranslatableProps.apply(base.TranslatableProps)
+	heuristics.UICallSites = c.UICallSites.apply(base.UICallSites)
+	heuristics.MetadataFields = c.MetadataFields.apply(base.MetadataFields)
+	heuristics.Allowlist = c.Allowlist.apply(base.Allowlist)
+
+	if len(c.Thresholds) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(c.Thresholds))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&heuristics.Thresholds); err != nil {
+			return base, fmt.Errorf("invalid thresholds: %w", err)
+		}
+	}
+
+	heuristics.Weights = make(map[string]float64, len(base.Weights))
+	for name, weight := range base.Weights {
+		heuristics.Weights[name] = weight
+	}
+	names := make([]string, 0, len(c.Weights))
+	for name := range c.Weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := heuristics.Weights[name]; !ok {
+			return base, fmt.Errorf("unknown signal %q in weights", name)
+		}
+		heuristics.Weights[name] = c.Weights[name]
+	}
+	return heuristics, nil
+}
+
+// matchesPattern reports whether text contains pattern. Patterns made of
+// letters only must match a whole word.
+func matchesPattern(text string, pattern string) bool {
+	if pattern != "" && strings.IndexFunc(pattern, func(r rune) bool { return !unicode.IsLetter(r) }) < 0 {
+		return containsWord(text, pattern)
+	}
+	return strings.Contains(text, pattern)
+}
+
+// containsWord reports whether word occurs in text and is not part of a
+// longer identifier or word
+func containsWord(text string, word string) bool {
+	isWordChar := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' }
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		before, after := ' ', ' '
+		if start > 0 {
+			before = lastRune(text[:start])
+		}
+		if end < len(text) {
+			after = []rune(text[end:])[0]
+		}
+		if !isWordChar(before) && !isWordChar(after) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func lastRune(text string) rune {
+	runes := []rune(text)
+	return runes[len(runes)-1]
+}
+
+// onlyAllowlisted reports whether text is made only of allowlisted names,
+// spaces and punctuation, and returns the first name found
+func onlyAllowlisted(text string, allowlist []string) (string, bool) {
+	rest, first := text, ""
+	for _, name := range allowlist {
+		if name == "" || !containsWord(rest, name) {
+			continue
+		}
+		if first == "" {
+			first = name
+		}
+		rest = strings.ReplaceAll(rest, name, " ")
+	}
+	if first == "" {
+		return "", false
+	}
+	return first, strings.IndexFunc(rest, isLetter) < 0
+}
@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ArgumentType is the kind of value an ICU message argument takes
+type ArgumentType string
+
+const (
+	// ArgumentString is a plain {name} argument or a select argument
+	ArgumentString ArgumentType = "string"
+	// ArgumentNumber is a number, plural or selectordinal argument
+	ArgumentNumber ArgumentType = "number"
+	// ArgumentDate is a date or time argument
+	ArgumentDate ArgumentType = "date"
+	// ArgumentTag is a rich text tag such as <b>...</b>, which next-intl
+	// renders with a function of the enclosed chunks
+	ArgumentTag ArgumentType = "tag"
+)
+
+// MessageArgument is an argument of an ICU message
+type MessageArgument struct {
+	Name string       `json:"name"`
+	Type ArgumentType `json:"type"`
+}
+
+// MessageArguments returns the arguments of an ICU message sorted by name,
+// including the arguments nested in plural and select branches. An argument
+// used several times has its most specific type: a number formatted with
+// {count, number} and printed with {count} is a number.
+func MessageArguments(message string) ([]MessageArgument, error) {
+	p := &icuParser{message: message, types: make(map[string]ArgumentType)}
+	if err := p.parseMessage(0, false); err != nil {
+		return nil, err
+	}
+
+	arguments := make([]MessageArgument, 0, len(p.types))
+	for name, argumentType := range p.types {
+		arguments = append(arguments, MessageArgument{Name: name, Type: argumentType})
+	}
+	sort.Slice(arguments, func(i, j int) bool { return arguments[i].Name < arguments[j].Name })
+	return arguments, nil
+}
+
+// icuParser scans ICU message syntax as next-intl reads it: arguments in
+// braces, rich text tags, and apostrophes quoting syntax characters
+type icuParser struct {
+	message string
+	pos     int
+	types   map[string]ArgumentType
+}
+
+func (p *icuParser) add(name string, argumentType ArgumentType) {
+	if current, ok := p.types[name]; ok && current != ArgumentString {
+		return
+	}
+	p.types[name] = argumentType
+}
+
+// parseMessage scans text up to the closing brace of the enclosing argument,
+// or to the end of the message at depth 0. In plural branches, # is the
+// number and can be quoted too.
+func (p *icuParser) parseMessage(depth int, plural bool) error {
+	for p.pos < len(p.message) {
+		switch c := p.message[p.pos]; c {
+		case '\'':
+			p.skipQuoted(plural)
+		case '{':
+			p.pos++
+			if err := p.parseArgument(depth + 1); err != nil {
+				return err
+			}
+		case '}':
+			if depth == 0 {
+				return fmt.Errorf("unexpected } at offset %d", p.pos)
+			}
+			return nil
+		case '<':
+			p.parseTag()
+		default:
+			p.pos++
+		}
+	}
+	if depth > 0 {
+		return fmt.Errorf("unclosed {")
+	}
+	return nil
+}
+
+// skipQuoted skips an apostrophe: two apostrophes are a literal one, and an
+// apostrophe before a syntax character quotes everything up to the next one
+func (p *icuParser) skipQuoted(plural bool) {
+	p.pos++
+	if p.pos >= len(p.message) {
+		return
+	}
+	next := p.message[p.pos]
+	if next == '\'' {
+		p.pos++
+		return
+	}
+	if !strings.ContainsRune("{}<>|", rune(next)) && !(plural && next == '#') {
+		return
+	}
+	if end := strings.IndexByte(p.message[p.pos:], '\''); end >= 0 {
+		p.pos += end + 1
+	} else {
+		p.pos = len(p.message)
+	}
+}
+
+// parseArgument scans an argument after its opening brace up to and
+// including its closing brace
+func (p *icuParser) parseArgument(depth int) error {
+	name := strings.TrimSpace(p.readUntil(",}"))
+	if name == "" {
+		return fmt.Errorf("argument without name at offset %d", p.pos)
+	}
+	if p.pos >= len(p.message) {
+		return fmt.Errorf("unclosed argument {%s", name)
+	}
+	if p.message[p.pos] == '}' {
+		p.pos++
+		p.add(name, ArgumentString)
+		return nil
+	}
+
+	p.pos++
+	kind := strings.TrimSpace(p.readUntil(",}"))
+	if p.pos >= len(p.message) {
+		return fmt.Errorf("unclosed argument {%s", name)
+	}
+	switch kind {
+	case "plural", "selectordinal":
+		p.add(name, ArgumentNumber)
+		return p.parseOptions(name, depth, true)
+	case "select":
+		p.add(name, ArgumentString)
+		return p.parseOptions(name, depth, false)
+	case "number":
+		p.add(name, ArgumentNumber)
+	case "date", "time":
+		p.add(name, ArgumentDate)
+	default:
+		p.add(name, ArgumentString)
+	}
+
+	// A style such as {price, number, ::currency/EUR} holds no arguments
+	if p.message[p.pos] == ',' {
+		p.readUntil("}")
+		if p.pos >= len(p.message) {
+			return fmt.Errorf("unclosed argument {%s", name)
+		}
+	}
+	p.pos++
+	return nil
+}
+
+// parseOptions scans the branches of a plural or select argument, such as
+// one {# item} other {# items}, up to and including its closing brace
+func (p *icuParser) parseOptions(name string, depth int, plural bool) error {
+	if p.message[p.pos] != ',' {
+		return fmt.Errorf("argument {%s} has no options", name)
+	}
+	p.pos++
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.message) {
+			return fmt.Errorf("unclosed argument {%s", name)
+		}
+		if p.message[p.pos] == '}' {
+			p.pos++
+			return nil
+		}
+
+		selector := p.readUntil(" \t\r\n{}")
+		if strings.HasPrefix(selector, "offset:") {
+			continue
+		}
+		p.skipSpace()
+		if selector == "" || p.pos >= len(p.message) || p.message[p.pos] != '{' {
+			return fmt.Errorf("option %q of argument {%s} has no message", selector, name)
+		}
+		p.pos++
+		if err := p.parseMessage(depth, plural); err != nil {
+			return err
+		}
+		// parseMessage stops at the closing brace of the branch
+		p.pos++
+	}
+}
+
+// parseTag records the name of an opening or self-closing tag. A < that
+// does not start a tag is text.
+func (p *icuParser) parseTag() {
+	start := p.pos
+	p.pos++
+	closing := p.pos < len(p.message) && p.message[p.pos] == '/'
+	if closing {
+		p.pos++
+	}
+	name := p.readWhile(func(c byte) bool {
+		return c == '-' || c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	})
+	rest := p.message[p.pos:]
+	switch {
+	case name != "" && strings.HasPrefix(rest, ">"):
+		p.pos++
+	case name != "" && !closing && strings.HasPrefix(rest, "/>"):
+		p.pos += 2
+	default:
+		p.pos = start + 1
+		return
+	}
+	if !closing {
+		p.add(name, ArgumentTag)
+	}
+}
+
+func (p *icuParser) readUntil(stop string) string {
+	start := p.pos
+	for p.pos < len(p.message) && !strings.ContainsRune(stop, rune(p.message[p.pos])) {
+		p.pos++
+	}
+	return p.message[start:p.pos]
+}
+
+func (p *icuParser) readWhile(match func(byte) bool) string {
+	start := p.pos
+	for p.pos < len(p.message) && match(p.message[p.pos]) {
+		p.pos++
+	}
+	return p.message[start:p.pos]
+}
+
+func (p *icuParser) skipSpace() {
+	p.readWhile(func(c byte) bool { return c == ' ' || c == '\t' || c == '\r' || c == '\n' })
+}
@@ -0,0 +1,110 @@
+package analyzer
+
+import "sort"
+
+// Issue is a single finding of an analysis
+type Issue struct {
+	Rule string
+	Translation
+}
+
+// ID identifies an issue independently of its line number, so that an issue
+// keeps its identity while code above it is edited.
+func (i Issue) ID() string {
+	return i.Rule + "\x00" + i.Locale + "\x00" + i.File + "\x00" + i.Key
+}
+
+// Issues flattens the overall results into a single list, ordered by rule,
+// then locale, file, line and key.
+func (r *AnalysisResult) Issues() []Issue {
+	issues := make([]Issue, 0, len(r.UnusedTranslations)+len(r.UndeclaredTranslations)+len(r.HardcodedStrings))
+	for _, translation := range r.UnusedTranslations {
+		issues = append(issues, Issue{Rule: RuleUnusedKey, Translation: translation})
+	}
+	for _, translation := range r.UndeclaredTranslations {
+		issues = append(issues, Issue{Rule: RuleUndeclaredKey, Translation: translation})
+	}
+	for _, translation := range r.HardcodedStrings {
+		issues = append(issues, Issue{Rule: RuleHardcodedString, Translation: translation})
+	}
+	for _, translation := range r.MissingTranslations {
+		issues = append(issues, Issue{Rule: RuleMissingTranslation, Translation: translation})
+	}
+	for _, translation := range r.UnusedSuppressions {
+		issues = append(issues, Issue{Rule: RuleUnusedSuppression, Translation: translation})
+	}
+	sortIssues(issues)
+	return issues
+}
+
+// HasIssues reports whether the results contain any finding
+func (r *AnalysisResult) HasIssues() bool {
+	return len(r.UnusedTranslations) > 0 || len(r.UndeclaredTranslations) > 0 || len(r.HardcodedStrings) > 0 || len(r.MissingTranslations) > 0 || len(r.UnusedSuppressions) > 0
+}
+
+// Fails reports whether any issue is at least as severe as threshold. A
+// threshold of SeverityOff never fails.
+func (r *AnalysisResult) Fails(threshold Severity) bool {
+	if threshold == SeverityOff {
+		return false
+	}
+	for _, issue := range r.Issues() {
+		if issue.Severity >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// CountBySeverity returns the number of issues of each severity
+func (r *AnalysisResult) CountBySeverity() map[Severity]int {
+	counts := make(map[Severity]int)
+	for _, issue := range r.Issues() {
+		counts[issue.Severity]++
+	}
+	return counts
+}
+
+// DiffIssues compares two issue lists and returns the issues only present in
+// current (added) and the issues only present in previous (resolved).
+func DiffIssues(previous []Issue, current []Issue) (added []Issue, resolved []Issue) {
+	previousIDs := make(map[string]bool, len(previous))
+	for _, issue := range previous {
+		previousIDs[issue.ID()] = true
+	}
+	currentIDs := make(map[string]bool, len(current))
+	for _, issue := range current {
+		currentIDs[issue.ID()] = true
+	}
+
+	for _, issue := range current {
+		if !previousIDs[issue.ID()] {
+			added = append(added, issue)
+		}
+	}
+	for _, issue := range previous {
+		if !currentIDs[issue.ID()] {
+			resolved = append(resolved, issue)
+		}
+	}
+	return added, resolved
+}
+
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		if a.Locale != b.Locale {
+			return a.Locale < b.Locale
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Key < b.Key
+	})
+}
@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// keyManifestVersion is the format version written to key manifests
+const keyManifestVersion = 1
+
+// KeyManifest lists the message keys of a reference locale with their
+// arguments. It describes a generated module exporting Object, a nested
+// object whose leaves are the keys, so that code outside React can reference
+// keys as constants such as messageKeys.HomePage.title. With
+// Config.KeyManifest, keys referenced through the object count as used.
+type KeyManifest struct {
+	Version int    `json:"version"`
+	Locale  string `json:"locale"`
+	// Module is the generated module, relative to the project root
+	Module string `json:"module"`
+	// Object is the name of the object the module exports
+	Object string        `json:"object"`
+	Keys   []ManifestKey `json:"keys"`
+}
+
+// ManifestKey is a message key and the arguments of its message
+type ManifestKey struct {
+	Key       string            `json:"key"`
+	Arguments []MessageArgument `json:"arguments,omitempty"`
+}
+
+// NewKeyManifest returns an empty manifest of the current version
+func NewKeyManifest(locale string, module string, object string) *KeyManifest {
+	return &KeyManifest{Version: keyManifestVersion, Locale: locale, Module: module, Object: object, Keys: make([]ManifestKey, 0)}
+}
+
+// LoadKeyManifest reads a key manifest from disk
+func LoadKeyManifest(path string) (*KeyManifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key manifest %s: %w", path, err)
+	}
+	return parseKeyManifest(path, content)
+}
+
+// LoadKeyManifestFS is LoadKeyManifest for a manifest read from fsys
+func LoadKeyManifestFS(fsys fs.FS, name string) (*KeyManifest, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key manifest %s: %w", name, err)
+	}
+	return parseKeyManifest(name, content)
+}
+
+func parseKeyManifest(path string, content []byte) (*KeyManifest, error) {
+	var manifest KeyManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing key manifest %s: %w", path, err)
+	}
+	if manifest.Version != keyManifestVersion {
+		return nil, fmt.Errorf("unsupported key manifest version %d in %s", manifest.Version, path)
+	}
+	if !jsIdentifierPattern.MatchString(manifest.Object) {
+		return nil, fmt.Errorf("invalid object name %q in key manifest %s", manifest.Object, path)
+	}
+	return &manifest, nil
+}
+
+// Marshal returns the manifest as indented JSON, the way generate-keys
+// writes it
+func (m *KeyManifest) Marshal() ([]byte, error) {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+var (
+	jsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+	// keyPathSegmentPattern matches a property of a key path: a name after a
+	// dot or a quoted name in brackets
+	keyPathSegmentPattern = regexp.MustCompile(`\.([A-Za-z_$][\w$]*)|\[(?:"([^"]*)"|'([^']*)')\]`)
+)
+
+// keyReferences finds the keys of a manifest referenced through its object
+type keyReferences struct {
+	pattern *regexp.Regexp
+	keys    map[string]bool
+}
+
+func (m *KeyManifest) references() *keyReferences {
+	refs := &keyReferences{
+		// The object name must not be the end of a longer identifier or a
+		// property of another object
+		pattern: regexp.MustCompile(`(?:^|[^\w$.])` + regexp.QuoteMeta(m.Object) + `((?:` + keyPathSegmentPattern.String() + `)+)`),
+		keys:    make(map[string]bool, len(m.Keys)),
+	}
+	for _, key := range m.Keys {
+		refs.keys[key.Key] = true
+	}
+	return refs
+}
+
+// apply adds the keys referenced in content to the translations of source as
+// translation calls. A key also used with a translation function keeps the
+// location of the call. source is not modified, as it may be cached.
+func (r *keyReferences) apply(file string, content []byte, source *SourceFile) *SourceFile {
+	matches := r.pattern.FindAllSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return source
+	}
+
+	translations := make(map[string]Translation, len(source.Translations)+len(matches))
+	for key, translation := range source.Translations {
+		translations[key] = translation
+	}
+	for _, match := range matches {
+		key, ok := r.resolve(string(content[match[2]:match[3]]))
+		if !ok {
+			continue
+		}
+		if _, used := translations[key]; used {
+			continue
+		}
+		translations[key] = Translation{
+			Key:  key,
+			File: file,
+			Line: strings.Count(string(content[:match[2]]), "\n") + 1,
+			Used: true,
+			Type: TypeTranslationCall,
+		}
+	}
+	return &SourceFile{Translations: translations, Suppressions: source.Suppressions, UnknownDirectives: source.UnknownDirectives}
+}
+
+// resolve returns the key of a property path such as .HomePage.title. The
+// longest prefix naming a key counts, so that messageKeys.Home.title.length
+// uses Home.title.
+func (r *keyReferences) resolve(path string) (string, bool) {
+	var segments []string
+	for _, match := range keyPathSegmentPattern.FindAllStringSubmatch(path, -1) {
+		segments = append(segments, match[1]+match[2]+match[3])
+	}
+	for n := len(segments); n > 0; n-- {
+		if key := strings.Join(segments[:n], "."); r.keys[key] {
+			return key, true
+		}
+	}
+	return "", false
+}
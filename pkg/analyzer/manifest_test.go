@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+func TestKeyConstantsInScripts(t *testing.T) {
+	manifest := NewKeyManifest("en", "src/keys.ts", "messageKeys")
+	for _, key := range []string{"Emails.count", "with-dash.x-y", "Emails.unused"} {
+		manifest.Keys = append(manifest.Keys, ManifestKey{Key: key})
+	}
+	content, err := manifest.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := fstest.MapFS{
+		"message-keys.json": {Data: content},
+		"messages/en.json":  {Data: []byte(`{"Emails":{"count":"{n} emails","unused":"Unused"},"with-dash":{"x-y":"Dash"}}`)},
+		// A TypeScript consumer without JSX
+		"src/mail.ts": {Data: []byte(`import { messageKeys } from './keys';
+
+export const subject = t(messageKeys.Emails.count, { n: 2 });
+export const dash = t(messageKeys["with-dash"]["x-y"]);
+const label = "Not a hardcoded string, as scripts are only read for key constants";
+`)},
+	}
+
+	unused := func(config *Config) []string {
+		t.Helper()
+		a, err := New(WithFS(fsys), WithConfig(config))
+		if err != nil {
+			t.Fatal(err)
+		}
+		results, err := a.Analyze(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results.HardcodedStrings) > 0 {
+			t.Errorf("hardcoded strings reported in scripts: %v", results.HardcodedStrings)
+		}
+		var keys []string
+		for _, translation := range results.UnusedTranslations {
+			keys = append(keys, translation.Key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	got := unused(&Config{KeyManifest: "message-keys.json"})
+	assertTexts(t, got, []string{"Emails.unused"})
+
+	// Without a manifest, scripts are not read at all
+	got = unused(DefaultConfig())
+	assertTexts(t, got, []string{"Emails", "Emails.count", "Emails.unused", "with-dash", "with-dash.x-y"})
+}
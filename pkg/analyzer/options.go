@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"runtime"
+)
+
+// Option configures an Analyzer created with New
+type Option func(*Analyzer)
+
+// WithDir analyzes the project directory dir. File paths in the results are
+// dir joined with the path of the file in the project, as with NewAnalyzer.
+// Combined with WithFS, the files are read from the file system while the
+// results still name them under dir.
+func WithDir(dir string) Option {
+	return func(a *Analyzer) {
+		a.projectPath = dir
+	}
+}
+
+// WithFS analyzes the project rooted at fsys. Without WithDir, file paths in
+// the results are the slash-separated paths of the files in fsys. A nil fsys
+// reads the directory given with WithDir.
+func WithFS(fsys fs.FS) Option {
+	return func(a *Analyzer) {
+		a.fsys = fsys
+	}
+}
+
+// WithFiles limits the analysis to the given files instead of every file of
+// the project. Paths are relative to the project root. Files that are neither
+// message files nor JSX/TSX source files are ignored.
+func WithFiles(files ...string) Option {
+	return func(a *Analyzer) {
+		a.files = make([]string, 0, len(files))
+		for _, file := range files {
+			a.files = append(a.files, path.Clean(filepath.ToSlash(file)))
+		}
+	}
+}
+
+// WithSourceRoots limits the source files to the given directories, relative
+// to the project root, instead of the whole project
+func WithSourceRoots(dirs ...string) Option {
+	return func(a *Analyzer) {
+		a.sourceRoots = cleanRoots(dirs)
+	}
+}
+
+// WithMessageRoots limits the message files to the given directories,
+// relative to the project root. Message files are still messages/<locale>.json
+// files, which a root may contain or be the messages directory of.
+func WithMessageRoots(dirs ...string) Option {
+	return func(a *Analyzer) {
+		a.messageRoots = cleanRoots(dirs)
+	}
+}
+
+func cleanRoots(dirs []string) []string {
+	roots := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		roots = append(roots, path.Clean(filepath.ToSlash(dir)))
+	}
+	return roots
+}
+
+// WithConfig sets the configuration of the analysis. A nil config means the
+// default configuration.
+func WithConfig(config *Config) Option {
+	return func(a *Analyzer) {
+		a.SetConfig(config)
+	}
+}
+
+// WithConcurrency sets the number of source files parsed in parallel. Values
+// below 1 use one worker per CPU.
+func WithConcurrency(workers int) Option {
+	return func(a *Analyzer) {
+		a.SetConcurrency(workers)
+	}
+}
+
+// WithCache sets the cache used to skip parsing unchanged files
+func WithCache(cache *ParseCache) Option {
+	return func(a *Analyzer) {
+		a.SetCache(cache)
+	}
+}
+
+// WithProgress sets a callback receiving progress updates
+func WithProgress(callback ProgressCallback) Option {
+	return func(a *Analyzer) {
+		a.SetProgressCallback(callback)
+	}
+}
+
+// New returns an analyzer configured by opts. Either WithDir or WithFS is
+// required.
+func New(opts ...Option) (*Analyzer, error) {
+	a := &Analyzer{
+		results:     newAnalysisResult(),
+		concurrency: runtime.NumCPU(),
+		config:      DefaultConfig(),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if a.fsys == nil {
+		if a.projectPath == "" {
+			return nil, errors.New("no project directory or file system given")
+		}
+		a.fsys = DirFS(a.projectPath)
+	}
+	for _, file := range a.files {
+		if !fs.ValidPath(file) {
+			return nil, fmt.Errorf("invalid file %q: paths must be relative to the project root", file)
+		}
+	}
+	for _, root := range append(append([]string{}, a.sourceRoots...), a.messageRoots...) {
+		if !fs.ValidPath(root) {
+			return nil, fmt.Errorf("invalid root %q: paths must be relative to the project root", root)
+		}
+	}
+	return a, nil
+}
@@ -1,225 +1,332 @@
 package analyzer
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
-type TranslationParser struct{}
+// Patterns used by ParseSourceFile. They are compiled once at package
+// initialisation so that parsing a file does not rebuild them per line.
+var (
+	useTranslationsPattern = regexp.MustCompile(`useTranslations\(['"]([^'"]+)['"]\)`)
+	getTranslationsPattern = regexp.MustCompile(`getTranslations\(['"]([^'"]+)['"]\)`)
 
-func NewTranslationParser() *TranslationParser {
-	return &TranslationParser{}
+	// Example: const adminT = getTranslations("Admin")
+	// Example: const t = useTranslations("Common")
+	varAssignmentPattern = regexp.MustCompile(`(?:const|let|var)\s+(\w+)\s*=\s*(?:useTranslations|getTranslations)\(['"]([^'"]+)['"]\)`)
+
+	// Example: const { t } = useTranslations("Common")
+	destructuredPattern = regexp.MustCompile(`(?:const|let|var)\s+\{\s*(\w+)[^\}]*\}\s*=\s*(?:useTranslations|getTranslations)\(['"]([^'"]+)['"]\)`)
+
+	// Standard pattern: t("key")
+	genericCallsPattern = regexp.MustCompile(`(\w+)\s*\(['"]([^'"]+)['"]\)`)
+
+	// Extended translation API calls (t.rich(), t.markup(), etc.)
+	extendedApiPattern = regexp.MustCompile(`(\w+)\.(?:rich|markup|raw|has)\s*\(['"]([^'"]+)['"]\)`)
+
+	hardcodedPatterns = []*regexp.Regexp{
+		// Text between JSX tags that's likely user-facing (between opening/closing tags)
+		// Example: <h1>Welcome to our site</h1>
+		regexp.MustCompile(`<(?:h[1-6]|p|li|span|div|button|a|label|td|th)\b[^>]*>([^<>{}\n]+\pL[^<>{}\n]*)</(?:h[1-6]|p|li|span|div|button|a|label|td|th)>`),
+
+		// Text between closing tag and opening tag that's not just whitespace
+		// Example: </Button>Click me<Button>
+		regexp.MustCompile(`>([^<>{}\n]{3,}\pL[^<>{}\n]{3,}|[^<>{}\n]*` + cjkLetter + `[^<>{}\n]*)<`),
+	}
+)
+
+// cjkLetter matches a Han, Hiragana, Katakana or Hangul character. CJK words
+// are often one or two characters long, so the hardcoded-string patterns do
+// not require as much text around them.
+const cjkLetter = `[\p{Han}\p{Hiragana}\p{Katakana}\p{Hangul}]`
+
+type TranslationParser struct {
+	heuristics Heuristics
+
+	// Lookup sets built from heuristics
+	translatableProps map[string]bool
+	uiCallSites       map[string]bool
+	metadataFields    map[string]bool
+}
+
+// ParserOption configures a TranslationParser
+type ParserOption func(*TranslationParser)
+
+// WithHeuristics replaces the default heuristics of the parser
+func WithHeuristics(heuristics Heuristics) ParserOption {
+	return func(p *TranslationParser) {
+		p.heuristics = heuristics
+	}
+}
+
+// WithTranslatableProps adds component props whose string values are checked
+// for hardcoded text
+func WithTranslatableProps(props ...string) ParserOption {
+	return func(p *TranslationParser) {
+		p.heuristics.TranslatableProps = append(copyStrings(p.heuristics.TranslatableProps), props...)
+	}
+}
+
+// NewTranslationParser returns a parser using DefaultHeuristics changed by
+// opts, applied in order
+func NewTranslationParser(opts ...ParserOption) *TranslationParser {
+	p := &TranslationParser{heuristics: DefaultHeuristics()}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.heuristics.Weights == nil {
+		p.heuristics.Weights = defaultWeights()
+	}
+	p.translatableProps = stringSet(p.heuristics.TranslatableProps)
+	p.uiCallSites = stringSet(p.heuristics.UICallSites)
+	p.metadataFields = stringSet(p.heuristics.MetadataFields)
+	return p
 }
 
+// Heuristics returns the heuristics the parser runs with
+func (p *TranslationParser) Heuristics() Heuristics {
+	return p.heuristics
+}
+
+func stringSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, entry := range list {
+		set[entry] = true
+	}
+	return set
+}
+
+// ConfigHash identifies the heuristics this parser runs with. Parse results
+// can only be reused between parsers that report the same hash.
+func (p *TranslationParser) ConfigHash() string {
+	config, _ := json.Marshal([]interface{}{p.heuristics, CommonPunctuation})
+	sum := sha256.Sum256(config)
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseTranslationFile parses a message file read from disk. Content read
+// from another file system is parsed with ParseTranslation.
 func (p *TranslationParser) ParseTranslationFile(filePath string) (map[string]Translation, error) {
-	declared := make(map[string]Translation)
-	
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("error reading file %s: %w", filePath, err)
 	}
 	
+	return p.ParseTranslation(filePath, content)
+}
+
+// ParseTranslation extracts the declared keys from the given message file
+// content. filePath is only used to label the returned translations.
+func (p *TranslationParser) ParseTranslation(filePath string, content []byte) (map[string]Translation, error) {
+	declared := make(map[string]Translation)
+	
 	var data map[string]interface{}
 	if err := json.Unmarshal(content, &data); err != nil {
 		return nil, fmt.Errorf("error parsing JSON in %s: %w", filePath, err)
 	}
 	
-	keys := p.extractKeys(data, "")
+	keys, err := p.extractKeys(content)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing JSON in %s: %w", filePath, err)
+	}
 	
 	for _, key := range keys {
-		declared[key] = Translation{
-			Key:      key,
+		declared[key.key] = Translation{
+			Key:      key.key,
 			File:     filePath,
-			Line:     0,
+			Line:     key.line,
 			Used:     false,
 			Declared: true,
+			Value:    key.value,
 		}
 	}
 	
 	return declared, nil
 }
 
-func (p *TranslationParser) extractKeys(data interface{}, prefix string) []string {
-	var keys []string
+// declaredKey is a key found in a message file
+type declaredKey struct {
+	key   string
+	line  int
+	value string
+}
+
+// extractKeys walks the tokens of a message file and returns every key,
+// namespaces included, with the line it is declared on. Leaf messages also
+// carry their value. Objects nested in arrays are addressed as key[index].
+func (p *TranslationParser) extractKeys(content []byte) ([]declaredKey, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
 	
-	switch v := data.(type) {
-	case map[string]interface{}:
-		for key, value := range v {
-			currentKey := key
-			if prefix != "" {
-				currentKey = prefix + "." + key
-			}
-			
-			keys = append(keys, currentKey)
-			
-			if nested, ok := value.(map[string]interface{}); ok {
-				keys = append(keys, p.extractKeys(nested, currentKey)...)
-			}
+	var keys []declaredKey
+	
+	var walk func(prefix string) (string, error)
+	walk = func(prefix string) (string, error) {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
 		}
-	case []interface{}:
-		for i, item := range v {
-			if nested, ok := item.(map[string]interface{}); ok {
-				currentKey := fmt.Sprintf("%s[%d]", prefix, i)
-				keys = append(keys, p.extractKeys(nested, currentKey)...)
+		
+		switch v := token.(type) {
+		case json.Delim:
+			switch v {
+			case '{':
+				for decoder.More() {
+					keyToken, err := decoder.Token()
+					if err != nil {
+						return "", err
+					}
+					key, _ := keyToken.(string)
+					line := bytes.Count(content[:decoder.InputOffset()], []byte("\n")) + 1
+					
+					currentKey := key
+					if prefix != "" {
+						currentKey = prefix + "." + key
+					}
+					
+					index := len(keys)
+					keys = append(keys, declaredKey{key: currentKey, line: line})
+					value, err := walk(currentKey)
+					if err != nil {
+						return "", err
+					}
+					keys[index].value = value
+				}
+			case '[':
+				for i := 0; decoder.More(); i++ {
+					if _, err := walk(fmt.Sprintf("%s[%d]", prefix, i)); err != nil {
+						return "", err
+					}
+				}
+			}
+			// Consume the closing delimiter
+			if _, err := decoder.Token(); err != nil {
+				return "", err
 			}
+			return "", nil
+		case string:
+			return v, nil
+		case json.Number:
+			return v.String(), nil
+		case bool:
+			return strconv.FormatBool(v), nil
 		}
+		return "", nil
 	}
 	
-	return keys
+	if _, err := walk(""); err != nil {
+		return nil, err
+	}
+	return keys, nil
 }
 
+// ParseSourceFile parses a source file read from disk. Content read from
+// another file system is parsed with ParseSource.
 func (p *TranslationParser) ParseSourceFile(filePath string) (map[string]Translation, error) {
-	used := make(map[string]Translation)
-	untranslated := make(map[string]Translation)
-	
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("error reading file %s: %w", filePath, err)
 	}
 	
+	return p.ParseSource(filePath, content).Translations, nil
+}
+
+// SourceFile is the parse result of a source file
+type SourceFile struct {
+	// Translations holds the translation calls, by full key, and the
+	// hardcoded strings, by text
+	Translations map[string]Translation
+	// Suppressions lists the suppression comments of the file
+	Suppressions []Suppression
+	// UnknownDirectives lists the comments naming a directive that does not
+	// exist, such as a misspelled one; they suppress nothing
+	UnknownDirectives []Suppression
+}
+
+// ParseSource extracts translation calls, hardcoded strings and suppression
+// comments from the given source content. filePath is only used to label the
+// results.
+func (p *TranslationParser) ParseSource(filePath string, content []byte) *SourceFile {
+	used := make(map[string]Translation)
+	untranslated := make(map[string]Translation)
+	var suppressions, unknown []Suppression
+	comments := &commentScanner{}
+	
 	fileContent := string(content)
 	lines := strings.Split(fileContent, "\n")
 	
-	currentNamespace := ""
-	
-	// Track variable assignments of translation functions
-	// Map of variable name -> namespace
-	translationVars := make(map[string]string)
+	scope := newScope()
+	metadata := &metadataBlock{}
 	
 	for lineNum, line := range lines {
 		lineNum++ // Convert to 1-based line numbers
 		
-		// Match direct useTranslations calls
-		useTranslationsMatch := regexp.MustCompile(`useTranslations\(['"]([^'"]+)['"]\)`).FindStringSubmatch(line)
-		if len(useTranslationsMatch) > 1 {
-			currentNamespace = useTranslationsMatch[1]
-			continue
-		}
-		
-		// Match direct getTranslations calls
-		getTranslationsMatch := regexp.MustCompile(`getTranslations\(['"]([^'"]+)['"]\)`).FindStringSubmatch(line)
-		if len(getTranslationsMatch) > 1 {
-			currentNamespace = getTranslationsMatch[1]
-			continue
-		}
-		
-		// Match variable assignments for translation functions
-		// Example: const adminT = getTranslations("Admin")
-		// Example: const t = useTranslations("Common")
-		varAssignmentPattern := regexp.MustCompile(`(?:const|let|var)\s+(\w+)\s*=\s*(?:useTranslations|getTranslations)\(['"]([^'"]+)['"]\)`)
-		varMatch := varAssignmentPattern.FindStringSubmatch(line)
-		if len(varMatch) > 2 {
-			varName := varMatch[1]
-			namespace := varMatch[2]
-			translationVars[varName] = namespace
-			continue
+		for _, comment := range comments.comments(line) {
+			suppression, ok := parseSuppression(filePath, comment, lineNum)
+			if !ok {
+				continue
+			}
+			if isDirective(suppression.Directive) {
+				suppressions = append(suppressions, suppression)
+			} else {
+				unknown = append(unknown, suppression)
+			}
 		}
 		
-		// Match destructured assignments
-		// Example: const { t } = useTranslations("Common")
-		destructuredPattern := regexp.MustCompile(`(?:const|let|var)\s+\{\s*(\w+)[^\}]*\}\s*=\s*(?:useTranslations|getTranslations)\(['"]([^'"]+)['"]\)`)
-		destructuredMatch := destructuredPattern.FindStringSubmatch(line)
-		if len(destructuredMatch) > 2 {
-			varName := destructuredMatch[1]
-			namespace := destructuredMatch[2]
-			translationVars[varName] = namespace
+		// Lines declaring a translation function contain no calls
+		if scope.update(line) {
 			continue
 		}
 		
-		// Process generic translation calls (standard pattern: t("key"))
-		genericCallsPattern := regexp.MustCompile(`(\w+)\s*\(['"]([^'"]+)['"]\)`)
-		genericCalls := genericCallsPattern.FindAllStringSubmatch(line, -1)
-		for _, match := range genericCalls {
+		// Process generic translation calls (standard pattern: t("key")) and
+		// extended translation API calls (t.rich(), t.markup(), etc.)
+		calls := genericCallsPattern.FindAllStringSubmatch(line, -1)
+		calls = append(calls, extendedApiPattern.FindAllStringSubmatch(line, -1)...)
+		for _, match := range calls {
 			if len(match) > 2 {
-				varName := match[1]
-				key := match[2]
-				
-				if key == "" {
-					continue
-				}
-				
-				// Check if this is a call to a known translation variable
-				namespace := ""
-				if varName == "t" {
-					namespace = currentNamespace
-				} else if ns, exists := translationVars[varName]; exists {
-					namespace = ns
-				} else {
+				fullKey, ok := scope.Resolve(match[1], match[2])
+				if !ok {
 					// Skip if not a translation function call
 					continue
 				}
 				
-				fullKey := key
-				if namespace != "" && !strings.Contains(key, ".") {
-					fullKey = namespace + "." + key
-				}
-				
 				used[fullKey] = Translation{
 					Key:      fullKey,
 					File:     filePath,
 					Line:     lineNum,
 					Used:     true,
 					Declared: false,
-					Type:     "translation_call",
+					Type:     TypeTranslationCall,
 				}
 			}
 		}
 		
-		// Process extended translation API calls (t.rich(), t.markup(), etc.)
-		// This now handles both standard t and variable-based translation functions
-		extendedApiPattern := regexp.MustCompile(`(\w+)\.(?:rich|markup|raw|has)\s*\(['"]([^'"]+)['"]\)`)
-		extendedCalls := extendedApiPattern.FindAllStringSubmatch(line, -1)
-		for _, match := range extendedCalls {
-			if len(match) > 2 {
-				varName := match[1]
-				key := match[2]
-				
-				if key == "" {
-					continue
-				}
-				
-				// Check if this is a call to a known translation variable
-				namespace := ""
-				if varName == "t" {
-					namespace = currentNamespace
-				} else if ns, exists := translationVars[varName]; exists {
-					namespace = ns
-				} else {
-					// Skip if not a translation function call
-					continue
-				}
-				
-				fullKey := key
-				if namespace != "" && !strings.Contains(key, ".") {
-					fullKey = namespace + "." + key
-				}
-				
-				used[fullKey] = Translation{
-					Key:      fullKey,
-					File:     filePath,
-					Line:     lineNum,
-					Used:     true,
-					Declared: false,
-					Type:     "translation_call",
-				}
+		// String literals in JSX expressions, props, UI calls and metadata
+		inMetadata := metadata.update(line)
+		for _, literal := range p.displayedLiterals(line, inMetadata) {
+			text := literalText(literal)
+			if !p.isDisplayedText(text) {
+				continue
 			}
-		}
-		
-		hardcodedPatterns := []*regexp.Regexp{
-			// Text between JSX tags that's likely user-facing (between opening/closing tags)
-			// Example: <h1>Welcome to our site</h1>
-			regexp.MustCompile(`<(?:h[1-6]|p|li|span|div|button|a|label|td|th)\b[^>]*>([^<>{}\n]+[a-zA-Z][^<>{}\n]*)</(?:h[1-6]|p|li|span|div|button|a|label|td|th)>`),
-			
-			// Text in specific JSX attributes that are likely to contain user-facing content
-			// Example: title="Click here to continue"
-			regexp.MustCompile(`(?:title|alt|placeholder|aria-label|description)=["']([^"'<>]{3,}[a-zA-Z][^"'<>]*)["']`),
-			
-			// Text between closing tag and opening tag that's not just whitespace
-			// Example: </Button>Click me<Button>
-			regexp.MustCompile(`>([^<>{}\n]{3,}[a-zA-Z][^<>{}\n]{3,})<`),
+			signals := append([]Signal{p.heuristics.signal(SignalDisplayContext, "")},
+				p.textSignals(strings.TrimSpace(templatePlaceholderPattern.ReplaceAllString(text, " ")))...)
+			p.addHardcodedString(untranslated, Translation{
+				Key:      text,
+				File:     filePath,
+				Line:     lineNum,
+				Used:     true,
+				Declared: false,
+				Type:     TypeHardcodedString,
+			}, signals)
 		}
 		
 		for _, pattern := range hardcodedPatterns {
@@ -239,8 +346,9 @@ func (p *TranslationParser) ParseSourceFile(filePath string) (map[string]Transla
 					}
 					
 					// Skip if this looks like a translation key (contains dots and is short, or matches common key patterns)
-					if (strings.Contains(text, ".") && len(text) < 20) || 
-					   (len(text) < 20 && !strings.Contains(text, " ") && 
+					length := utf8.RuneCountInString(text)
+					if (strings.Contains(text, ".") && length < 20) || 
+					   (length < 20 && !strings.Contains(text, " ") && 
 					    (strings.Contains(text, "button") || strings.Contains(text, "navigation") || 
 					     strings.Contains(text, "title") || strings.Contains(text, "welcome") || 
 					     strings.Contains(text, "about") || strings.Contains(text, "description") ||
@@ -249,8 +357,8 @@ func (p *TranslationParser) ParseSourceFile(filePath string) (map[string]Transla
 					}
 					
 									// Skip imports and function names (including destructured imports)
-				if strings.Contains(text, "import") || strings.Contains(text, "export") || 
-				   strings.Contains(text, "function") || strings.Contains(text, "const") {
+				if containsWord(text, "import") || containsWord(text, "export") || 
+				   containsWord(text, "function") || containsWord(text, "const") {
 					continue
 				}
 				
@@ -270,8 +378,10 @@ func (p *TranslationParser) ParseSourceFile(filePath string) (map[string]Transla
 					continue
 				}
 				
-				// Skip single words that are likely variable names
-				if !strings.Contains(text, " ") && len(text) < 15 {
+				// Skip single words that are likely variable names, unless they
+				// are known UI words or product terms. Identifiers are ASCII in
+				// practice, and CJK text has no spaces to split on.
+				if !strings.Contains(text, " ") && length < 15 && isASCII(text) && !p.isUIWord(text) {
 					continue
 				}
 				
@@ -286,18 +396,6 @@ func (p *TranslationParser) ParseSourceFile(filePath string) (map[string]Transla
 					continue
 				}
 					
-					// Skip technical patterns using the constants.go definitions
-					isTechnical := false
-					for _, pattern := range TechnicalPatterns {
-						if strings.Contains(text, pattern) {
-							isTechnical = true
-							break
-						}
-					}
-					if isTechnical {
-						continue
-					}
-					
 					// Skip comments
 					if strings.HasPrefix(text, "/*") || strings.HasPrefix(text, "//") || 
 					   strings.Contains(text, "*/") {
@@ -313,7 +411,7 @@ func (p *TranslationParser) ParseSourceFile(filePath string) (map[string]Transla
 					}
 					
 					// Skip if it's just whitespace or very short
-					if len(text) < MinTextLength || strings.TrimSpace(text) == "" {
+					if length < p.heuristics.Thresholds.MinCJKTextLength || strings.TrimSpace(text) == "" {
 						continue
 					}
 					
@@ -328,7 +426,7 @@ func (p *TranslationParser) ParseSourceFile(filePath string) (map[string]Transla
 					}
 					
 					// Skip if it's a single character (except common punctuation)
-					if len(text) == 1 {
+					if length == 1 {
 						isPunctuation := false
 						for _, punct := range CommonPunctuation {
 							if text == punct {
@@ -341,16 +439,15 @@ func (p *TranslationParser) ParseSourceFile(filePath string) (map[string]Transla
 						}
 					}
 					
-					if p.isUserFacingText(text) {
-						untranslated[text] = Translation{
-							Key:      text,
-							File:     filePath,
-							Line:     lineNum,
-							Used:     true,
-							Declared: false,
-							Type:     "hardcoded_string",
-						}
-					}
+					signals := append([]Signal{p.heuristics.signal(SignalTagContent, "")}, p.textSignals(text)...)
+					p.addHardcodedString(untranslated, Translation{
+						Key:      text,
+						File:     filePath,
+						Line:     lineNum,
+						Used:     true,
+						Declared: false,
+						Type:     TypeHardcodedString,
+					}, signals)
 				}
 			}
 		}
@@ -360,110 +457,276 @@ func (p *TranslationParser) ParseSourceFile(filePath string) (map[string]Transla
 		used[key] = translation
 	}
 	
-	return used, nil
+	return &SourceFile{Translations: used, Suppressions: suppressions, UnknownDirectives: unknown}
+}
+
+// Scope describes the translation functions visible at a point of a source
+// file.
+type Scope struct {
+	// Namespace is the namespace of the default translation function t
+	Namespace string
+	// Variables maps translation function variables to their namespace
+	Variables map[string]string
+}
+
+func newScope() *Scope {
+	return &Scope{Variables: make(map[string]string)}
+}
+
+// ScopeAt returns the translation functions declared in content up to and
+// including the given 0-based line.
+func (p *TranslationParser) ScopeAt(content []byte, line int) *Scope {
+	scope := newScope()
+	for i, text := range strings.Split(string(content), "\n") {
+		if i > line {
+			break
+		}
+		scope.update(text)
+	}
+	return scope
+}
+
+// update records the translation function declared on line, if any, and
+// reports whether one was found.
+func (s *Scope) update(line string) bool {
+	// Match direct useTranslations and getTranslations calls
+	if match := useTranslationsPattern.FindStringSubmatch(line); len(match) > 1 {
+		s.Namespace = match[1]
+		return true
+	}
+	if match := getTranslationsPattern.FindStringSubmatch(line); len(match) > 1 {
+		s.Namespace = match[1]
+		return true
+	}
+	
+	// Match variable assignments and destructured assignments
+	if match := varAssignmentPattern.FindStringSubmatch(line); len(match) > 2 {
+		s.Variables[match[1]] = match[2]
+		return true
+	}
+	if match := destructuredPattern.FindStringSubmatch(line); len(match) > 2 {
+		s.Variables[match[1]] = match[2]
+		return true
+	}
+	return false
+}
+
+// NamespaceOf returns the namespace of the translation function fn, and
+// false when fn is not a known translation function.
+func (s *Scope) NamespaceOf(fn string) (string, bool) {
+	if fn == "t" {
+		return s.Namespace, true
+	}
+	namespace, exists := s.Variables[fn]
+	return namespace, exists
 }
 
-func (p *TranslationParser) isUserFacingText(text string) bool {
+// Resolve returns the full key looked up by calling fn with key, and false
+// when fn is not a known translation function.
+func (s *Scope) Resolve(fn string, key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	
+	namespace, ok := s.NamespaceOf(fn)
+	if !ok {
+		return "", false
+	}
+	
+	// next-intl always resolves keys relative to the namespace, including
+	// nested keys such as t('button.save')
+	if namespace != "" {
+		return namespace + "." + key, true
+	}
+	return key, true
+}
+
+// textSignals returns the heuristic signals that fire for text, which
+// together say how likely text is to be user-facing. See Confidence.
+func (p *TranslationParser) textSignals(text string) []Signal {
+	heuristics := &p.heuristics
+	thresholds := heuristics.Thresholds
+	var signals []Signal
+	add := func(name string, detail string) {
+		signals = append(signals, heuristics.signal(name, detail))
+	}
+
+	// Lengths are counted in characters, not bytes, so that text in
+	// multi-byte scripts is measured like Latin text
+	length := utf8.RuneCountInString(text)
+	textScript := detectScript(text)
+	if textScript == scriptNone {
+		add(SignalNoLetters, "")
+		return signals
+	}
+
+	// Brand and product names that never need translating
+	if name, ok := onlyAllowlisted(text, heuristics.Allowlist); ok {
+		add(SignalAllowlisted, name)
+		return signals
+	}
+
 	// Handle very short but common UI strings from our predefined list
-	if len(text) >= 2 && len(text) <= 4 {
-		for _, word := range ShortUIWords {
+	shortUIWord := false
+	if length >= 2 && length <= 4 {
+		for _, word := range heuristics.ShortUIWords {
 			if strings.EqualFold(text, word) {
-				return true
+				add(SignalShortUIWord, word)
+				shortUIWord = true
+				break
 			}
 		}
 	}
 
 	// Minimum length check
-	if len(text) < MinTextLength {
-		return false
+	minLength := thresholds.MinTextLength
+	if textScript == scriptCJK {
+		minLength = thresholds.MinCJKTextLength
+	}
+	if length < minLength && !shortUIWord {
+		add(SignalTooShort, "")
 	}
 	
-	// Skip strings that look like code
-	if strings.ContainsAny(text, "{}[]()<>=+*/") {
-		return false
+	// Strings that look like code
+	if i := strings.IndexAny(text, "{}[]()<>=+*/"); i >= 0 {
+		add(SignalCodeCharacters, text[i:i+1])
 	}
 	
-	// Skip camelCase or snake_case identifiers which are likely code
+	// camelCase or snake_case identifiers which are likely code
 	if !strings.Contains(text, " ") && 
-	   (strings.ContainsRune(text, '_') || 
-		(strings.ToLower(text) != text && text[0] != strings.ToUpper(text)[0])) {
-		return false
+	   (strings.ContainsRune(text, '_') || isCamelCase(text)) {
+		add(SignalIdentifier, "")
 	}
 
-	// Check for technical patterns
-	for _, pattern := range TechnicalPatterns {
-		if strings.Contains(text, pattern) {
-			return false
+	// Technical patterns
+	for _, pattern := range heuristics.TechnicalPatterns {
+		if matchesPattern(text, pattern) {
+			add(SignalTechnicalPattern, pattern)
+			break
 		}
 	}
 
-	// Check for common UI/UX patterns that indicate user-facing content
-	for _, pattern := range UIPatterns {
+	// Common UI/UX patterns that indicate user-facing content
+	for _, pattern := range heuristics.UIPatterns {
 		if strings.Contains(text, pattern) {
-			return true
+			add(SignalUIPattern, pattern)
+			break
 		}
 	}
 
-	// Count words - strings with multiple words are more likely to be user-facing
-	words := strings.Fields(text)
-	if len(words) >= MinWordsForSentence {
-		// Check if it's a proper sentence (starts with capital, ends with punctuation)
-		if len(text) > 3 {
-			firstChar := text[0]
-			lastChar := text[len(text)-1]
-			if (firstChar >= 'A' && firstChar <= 'Z') && 
-			   (lastChar == '.' || lastChar == '!' || lastChar == '?') {
-				return true
-			}
-		}
-		
-		// Even without proper sentence structure, multi-word phrases are often translatable
-		return true
-	}
-
-	// Enhanced alphabetic ratio check with adjusted thresholds
-	alphaCount := 0
+	// Count letters of any script, treating combining marks as letters
+	letterCount := 0
 	spaceCount := 0
 	punctCount := 0
 	
 	for _, char := range text {
-		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') {
-			alphaCount++
-		} else if char == ' ' {
+		if isLetter(char) {
+			letterCount++
+		} else if unicode.IsSpace(char) {
 			spaceCount++
-		} else if strings.ContainsRune(".,!?:;-—–…", char) {
+		} else if isCommonPunctuation(char) {
 			punctCount++
 		}
 	}
-	
-	// Skip if mostly non-alphabetic (excluding spaces and common punctuation)
-	nonAlphaCount := len(text) - alphaCount - spaceCount - punctCount
-	
-	// For longer text, require more alphabetic characters
-	if len(text) > LongTextThreshold {
-		return alphaCount >= int(float64(len(text))*LongTextRatio) && 
-		       len(words) >= MinWordsForSentence
+	ratio := fmt.Sprintf("%d/%d", letterCount, length)
+
+	// CJK text has no spaces between words, so only the letter ratio applies
+	if textScript == scriptCJK {
+		threshold := thresholds.CJKShortTextRatio
+		if length > thresholds.CJKLongTextThreshold {
+			threshold = thresholds.CJKLongTextRatio
+		}
+		if letterCount >= int(float64(length)*threshold) {
+			add(SignalLetterRatio, ratio)
+		} else {
+			add(SignalLowLetterRatio, ratio)
+		}
+		return signals
 	}
-	
-	// For medium text, be more strict
-	if len(text) > MediumTextThreshold {
-		return alphaCount >= int(float64(len(text))*MediumTextRatio) &&
-			   nonAlphaCount < len(text)/3
+
+	// Count words - strings with multiple words are more likely to be user-facing
+	words := strings.Fields(text)
+	minWords := thresholds.MinWordsForSentence
+	if textScript == scriptAlphabetic {
+		minWords = thresholds.MinWordsForNonLatinSentence
+	}
+	if len(words) >= minWords {
+		// A proper sentence starts with a capital, or a letter of a caseless
+		// script such as Arabic or Hebrew, and ends with punctuation
+		firstChar, _ := utf8.DecodeRuneInString(text)
+		lastChar, _ := utf8.DecodeLastRuneInString(text)
+		if length > 3 && (unicode.IsUpper(firstChar) || isCaseless(firstChar)) && isSentenceEnd(lastChar) {
+			add(SignalSentence, "")
+		} else {
+			// Even without proper sentence structure, multi-word phrases are often translatable
+			add(SignalMultiWord, strconv.Itoa(len(words)))
+		}
+		return signals
 	}
+
+	// Mostly non-alphabetic text (excluding spaces and common punctuation)
+	nonLetterCount := length - letterCount - spaceCount - punctCount
 	
-	// For short text, be very strict to avoid false positives
-	return alphaCount >= int(float64(len(text))*ShortTextRatio)
+	var enoughLetters bool
+	switch {
+	case length > thresholds.LongTextThreshold:
+		// Longer text should read as a phrase
+		enoughLetters = letterCount >= int(float64(length)*thresholds.LongTextRatio)
+		add(SignalFewWords, strconv.Itoa(len(words)))
+	case length > thresholds.MediumTextThreshold:
+		enoughLetters = letterCount >= int(float64(length)*thresholds.MediumTextRatio) && nonLetterCount < length/3
+	default:
+		// For short text, be very strict to avoid false positives
+		enoughLetters = letterCount >= int(float64(length)*thresholds.ShortTextRatio)
+	}
+	if enoughLetters {
+		add(SignalLetterRatio, ratio)
+	} else {
+		add(SignalLowLetterRatio, ratio)
+	}
+	return signals
+}
+
+// isUIWord reports whether word is one of the short UI words or UI patterns
+// of the heuristics
+func (p *TranslationParser) isUIWord(word string) bool {
+	for _, uiWord := range p.heuristics.ShortUIWords {
+		if strings.EqualFold(word, uiWord) {
+			return true
+		}
+	}
+	for _, pattern := range p.heuristics.UIPatterns {
+		if word == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// isCamelCase reports whether text starts with a lowercase letter and
+// contains an uppercase letter, like a JavaScript identifier
+func isCamelCase(text string) bool {
+	first, _ := utf8.DecodeRuneInString(text)
+	if !unicode.IsLower(first) {
+		return false
+	}
+	for _, char := range text {
+		if unicode.IsUpper(char) {
+			return true
+		}
+	}
+	return false
 }
 
 // isNumeric checks if a string is mostly numeric
 func (p *TranslationParser) isNumeric(text string) bool {
 	digitCount := 0
 	for _, char := range text {
-		if char >= '0' && char <= '9' {
+		if unicode.IsDigit(char) {
 			digitCount++
 		}
 	}
-	return digitCount >= len(text)/2
+	return digitCount >= utf8.RuneCountInString(text)/2
 }
 
 func (p *TranslationParser) MergeTranslationMaps(maps ...map[string]Translation) map[string]Translation {
@@ -0,0 +1,31 @@
+package analyzer
+
+import "testing"
+
+func TestScopeResolve(t *testing.T) {
+	p := NewTranslationParser()
+	common := p.ScopeAt([]byte(`const t = useTranslations('Common');`), 0)
+	root := p.ScopeAt([]byte(`const t = useTranslations();`), 0)
+
+	tests := []struct {
+		name  string
+		scope *Scope
+		fn    string
+		key   string
+		want  string
+		ok    bool
+	}{
+		{"key", common, "t", "save", "Common.save", true},
+		// Nested keys are relative to the namespace as well
+		{"nested key", common, "t", "button.save", "Common.button.save", true},
+		{"no namespace", root, "t", "Common.save", "Common.save", true},
+		{"not a translation function", common, "format", "save", "", false},
+		{"empty key", common, "t", "", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.scope.Resolve(tt.fn, tt.key)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: Resolve(%q, %q) = %q, %v, want %q, %v", tt.name, tt.fn, tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
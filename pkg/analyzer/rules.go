@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Rule IDs of the checks performed by the analyzer
+const (
+	RuleUnusedKey       = "unused-key"
+	RuleUndeclaredKey   = "undeclared-key"
+	RuleHardcodedString = "hardcoded-string"
+	// RuleMissingTranslation reports keys that some locales declare and
+	// others lack
+	RuleMissingTranslation = "missing-translation"
+	// RuleUnusedSuppression reports suppression comments that match no issue
+	RuleUnusedSuppression = "unused-suppression"
+)
+
+// Severity is the importance of an issue. SeverityOff disables a rule.
+type Severity int
+
+const (
+	SeverityOff Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+)
+
+var severityNames = map[Severity]string{
+	SeverityOff:     "off",
+	SeverityInfo:    "info",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+// ParseSeverity parses "off" (or "none"), "info", "warning" (or "warn") and
+// "error".
+func ParseSeverity(text string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "off", "none":
+		return SeverityOff, nil
+	case "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return SeverityOff, fmt.Errorf("unknown severity %q (expected error, warning, info or off)", text)
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	severity, err := ParseSeverity(text)
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// Rule describes a check performed by the analyzer
+type Rule struct {
+	ID              string
+	Description     string
+	DefaultSeverity Severity
+}
+
+// Rules lists every rule known to the analyzer
+var Rules = []Rule{
+	{
+		ID:              RuleUnusedKey,
+		Description:     "A key is declared in a message file but never used in source files",
+		DefaultSeverity: SeverityError,
+	},
+	{
+		ID:              RuleUndeclaredKey,
+		Description:     "A key is used in source files but not declared in a locale's message file",
+		DefaultSeverity: SeverityError,
+	},
+	{
+		ID:              RuleHardcodedString,
+		Description:     "User-facing text is hardcoded in a source file instead of being translated",
+		DefaultSeverity: SeverityError,
+	},
+	{
+		ID:              RuleMissingTranslation,
+		Description:     "A key is declared in some locales' message files but missing in others",
+		DefaultSeverity: SeverityWarning,
+	},
+	{
+		ID:              RuleUnusedSuppression,
+		Description:     "A suppression comment does not silence any issue",
+		DefaultSeverity: SeverityWarning,
+	},
+}
+
+// LookupRule returns the rule with the given ID
+func LookupRule(id string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// applyRules sets the severity of every issue according to config and drops
+// the issues of rules that are turned off.
+func applyRules(results *AnalysisResult, config *Config, projectPath string) {
+	rate := func(rule string) func(*Translation) bool {
+		return func(translation *Translation) bool {
+			translation.Severity = config.SeverityFor(rule, relativePath(projectPath, translation.File))
+			return translation.Severity == SeverityOff
+		}
+	}
+
+	results.UnusedTranslations = rateTranslations(results.UnusedTranslations, rate(RuleUnusedKey))
+	results.UndeclaredTranslations = rateTranslations(results.UndeclaredTranslations, rate(RuleUndeclaredKey))
+	results.HardcodedStrings = rateTranslations(results.HardcodedStrings, rate(RuleHardcodedString))
+	results.MissingTranslations = rateTranslations(results.MissingTranslations, rate(RuleMissingTranslation))
+	results.UnusedSuppressions = rateTranslations(results.UnusedSuppressions, rate(RuleUnusedSuppression))
+
+	for _, localeResult := range results.LocaleResults {
+		localeResult.UnusedTranslations = rateTranslations(localeResult.UnusedTranslations, rate(RuleUnusedKey))
+		localeResult.UndeclaredTranslations = rateTranslations(localeResult.UndeclaredTranslations, rate(RuleUndeclaredKey))
+	}
+}
+
+// rateTranslations calls rate on every translation and keeps those for which
+// it returns false
+func rateTranslations(translations []Translation, rate func(*Translation) bool) []Translation {
+	kept := make([]Translation, 0, len(translations))
+	for _, translation := range translations {
+		if !rate(&translation) {
+			kept = append(kept, translation)
+		}
+	}
+	return kept
+}
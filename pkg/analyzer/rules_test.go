@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSeverityFor(t *testing.T) {
+	config := &Config{
+		Rules: map[string]Severity{
+			RuleHardcodedString: SeverityWarning,
+			RuleUnusedKey:       SeverityOff,
+		},
+		Overrides: []RuleOverride{
+			{Files: []string{"src/legacy/**", "**/*.stories.tsx"}, Rules: map[string]Severity{RuleHardcodedString: SeverityOff}},
+			{Files: []string{"src/legacy/important/**"}, Rules: map[string]Severity{RuleHardcodedString: SeverityError}},
+			{Files: []string{"messages/de.json"}, Rules: map[string]Severity{RuleUnusedKey: SeverityInfo}},
+		},
+	}
+	tests := []struct {
+		rule string
+		file string
+		want Severity
+	}{
+		// Defaults of the rules the configuration leaves alone
+		{RuleUndeclaredKey, "src/page.tsx", SeverityError},
+		{RuleMissingTranslation, "messages/en.json", SeverityWarning},
+		{RuleUnusedSuppression, "src/page.tsx", SeverityWarning},
+		{"no-such-rule", "src/page.tsx", SeverityOff},
+		// Rules configured globally
+		{RuleHardcodedString, "src/page.tsx", SeverityWarning},
+		{RuleUnusedKey, "messages/en.json", SeverityOff},
+		// Overrides of matching files
+		{RuleHardcodedString, "src/legacy/page.tsx", SeverityOff},
+		{RuleHardcodedString, "src/ui/button.stories.tsx", SeverityOff},
+		{RuleUnusedKey, "messages/de.json", SeverityInfo},
+		// Later overrides win
+		{RuleHardcodedString, "src/legacy/important/page.tsx", SeverityError},
+		// Overrides only change the rules they name
+		{RuleUndeclaredKey, "src/legacy/page.tsx", SeverityError},
+	}
+	for _, tt := range tests {
+		if got := config.SeverityFor(tt.rule, tt.file); got != tt.want {
+			t.Errorf("SeverityFor(%s, %s) = %s, want %s", tt.rule, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestMissingTranslations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"messages/en.json": {Data: []byte(`{
+  "Common": {
+    "save": "Save",
+    "cancel": "Cancel"
+  }
+}`)},
+		"messages/de.json": {Data: []byte(`{"Common": {"save": "Speichern"}, "Title": "Titel", "Extra": "Extra"}`)},
+		"messages/fr.json": {Data: []byte(`{"Common": {"save": "Enregistrer", "cancel": "Annuler"}}`)},
+	}
+	config := &Config{Overrides: []RuleOverride{
+		{Files: []string{"messages/de.json"}, Rules: map[string]Severity{RuleMissingTranslation: SeverityError}},
+	}}
+	a, err := New(WithFS(fsys), WithConfig(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := a.Analyze(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each missing locale is reported at the declaration of the key in the
+	// first locale declaring it; namespaces are not keys
+	want := []string{
+		"messages/de.json:1 Extra en error",
+		"messages/de.json:1 Extra fr error",
+		"messages/de.json:1 Title en error",
+		"messages/de.json:1 Title fr error",
+		"messages/en.json:4 Common.cancel de warning",
+	}
+	var got []string
+	for _, translation := range results.MissingTranslations {
+		got = append(got, fmt.Sprintf("%s:%d %s %s %s", translation.File, translation.Line, translation.Key, translation.Locale, translation.Severity))
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("missing translations:\n%v\nwant:\n%v", got, want)
+	}
+	for _, issue := range results.Issues() {
+		if issue.Rule == RuleMissingTranslation {
+			return
+		}
+	}
+	t.Error("missing translations are not issues")
+}
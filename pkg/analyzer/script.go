@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// script classifies the writing system of a text for the hardcoded-string
+// heuristics
+type script int
+
+const (
+	// scriptNone is text without letters
+	scriptNone script = iota
+	// scriptLatin is text mostly written in Latin letters
+	scriptLatin
+	// scriptAlphabetic is text mostly written in another script that separates
+	// words with spaces, such as Cyrillic, Greek, Arabic or Hebrew
+	scriptAlphabetic
+	// scriptCJK is text mostly written in Han, Hiragana, Katakana or Hangul,
+	// where a single character often carries a whole word
+	scriptCJK
+)
+
+var cjkScripts = []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul}
+
+// isLetter reports whether r counts as a letter. Combining marks are included
+// so that vowel signs and diacritics (Arabic harakat, Hebrew niqqud,
+// Devanagari matras) do not lower the letter ratio of a text.
+func isLetter(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r)
+}
+
+// isCaseless reports whether r is a letter of a script without upper and
+// lower case, which therefore cannot start a sentence with a capital
+func isCaseless(r rune) bool {
+	return unicode.IsLetter(r) && !unicode.IsUpper(r) && !unicode.IsLower(r) && !unicode.IsTitle(r)
+}
+
+// isCommonPunctuation reports whether r is one of CommonPunctuation
+func isCommonPunctuation(r rune) bool {
+	for _, punct := range CommonPunctuation {
+		if utf8.RuneCountInString(punct) == 1 && strings.ContainsRune(punct, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSentenceEnd reports whether r terminates a sentence in any of the
+// supported scripts
+func isSentenceEnd(r rune) bool {
+	return strings.ContainsRune(".!?。！？؟", r)
+}
+
+// detectScript returns the script of the majority of the letters of text
+func detectScript(text string) script {
+	latin, cjk, other := 0, 0, 0
+	for _, r := range text {
+		switch {
+		case !isLetter(r):
+		case unicode.In(r, cjkScripts...):
+			cjk++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		default:
+			other++
+		}
+	}
+
+	switch {
+	case latin+cjk+other == 0:
+		return scriptNone
+	case cjk > 0 && cjk >= latin && cjk >= other:
+		return scriptCJK
+	case other > latin:
+		return scriptAlphabetic
+	default:
+		return scriptLatin
+	}
+}
+
+// isASCII reports whether text only contains ASCII characters, as JavaScript
+// identifiers in practice do
+func isASCII(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"os"
+	"sort"
+	"testing"
+)
+
+func TestDetectScript(t *testing.T) {
+	tests := []struct {
+		text string
+		want script
+	}{
+		{"Save changes", scriptLatin},
+		{"Сохранить", scriptAlphabetic},
+		{"Καλώς ήρθατε", scriptAlphabetic},
+		{"مرحبا بكم", scriptAlphabetic},
+		{"ברוכים הבאים", scriptAlphabetic},
+		{"保存", scriptCJK},
+		{"ようこそ", scriptCJK},
+		{"저장하기", scriptCJK},
+		// Mixed text has the script of the majority of its letters, CJK on a tie
+		{"Email адрес пользователя", scriptAlphabetic},
+		{"Welcome to 東京", scriptLatin},
+		{"購入するiPad", scriptCJK},
+		// Digits, punctuation and symbols are not letters
+		{"123", scriptNone},
+		{"。、！？", scriptNone},
+		{"--- ...", scriptNone},
+		{"", scriptNone},
+	}
+	for _, tt := range tests {
+		if got := detectScript(tt.text); got != tt.want {
+			t.Errorf("detectScript(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+// userFacing reports whether text alone scores as a hardcoded string with the
+// default heuristics
+func userFacing(text string) bool {
+	p := NewTranslationParser()
+	return p.heuristics.Confidence(p.textSignals(text)) >= DefaultMinConfidence
+}
+
+func TestNonLatinTextIsUserFacing(t *testing.T) {
+	tests := []struct {
+		script string
+		text   string
+		want   bool
+	}{
+		// CJK: short words qualify without spaces or a word count
+		{"Japanese", "ようこそ", true},
+		{"Japanese", "この操作は元に戻せません。", true},
+		{"Chinese", "保存", true},
+		{"Chinese", "欢迎使用我们的应用", true},
+		{"Chinese", "加载中…", true},
+		{"Korean", "저장하기", true},
+		{"CJK single character", "保", false},
+		{"CJK digits", "2024", false},
+		{"CJK mostly digits", "2024年12月31日", false},
+		{"CJK punctuation", "。、！", false},
+
+		// Cyrillic: two words make a phrase, a single word needs enough letters
+		{"Cyrillic", "Добро пожаловать", true},
+		{"Cyrillic", "Сохранить", true},
+		{"Cyrillic", "Ваши изменения были сохранены.", true},
+		{"Cyrillic digits", "123 456", false},
+		{"Cyrillic mixed punctuation", "—…!?", false},
+
+		// Right-to-left scripts are caseless, so sentences start with any letter
+		{"Arabic", "مرحبا بكم في تطبيقنا", true},
+		{"Arabic", "حفظ التغييرات", true},
+		{"Arabic", "هل أنت متأكد؟", true},
+		{"Hebrew", "ברוכים הבאים לאפליקציה שלנו", true},
+		{"Hebrew", "שמור", true},
+		{"Arabic punctuation", "،؛؟", false},
+	}
+	for _, tt := range tests {
+		if got := userFacing(tt.text); got != tt.want {
+			p := NewTranslationParser()
+			t.Errorf("%s: userFacing(%q) = %v, want %v (signals %v)", tt.script, tt.text, got, tt.want, p.textSignals(tt.text))
+		}
+	}
+}
+
+// hardcodedTexts returns the sorted texts of the hardcoded strings of the
+// test-data source file name that have at least the default confidence
+func hardcodedTexts(t *testing.T, name string) []string {
+	t.Helper()
+	content, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, translation := range NewTranslationParser().ParseSource(name, content).Translations {
+		if translation.Type == TypeHardcodedString && translation.Confidence >= DefaultMinConfidence {
+			texts = append(texts, translation.Key)
+		}
+	}
+	sort.Strings(texts)
+	return texts
+}
+
+// assertTexts compares got with want, both sorted
+func assertTexts(t *testing.T, got []string, want []string) {
+	t.Helper()
+	sort.Strings(want)
+	missing, unexpected := difference(want, got), difference(got, want)
+	for _, text := range missing {
+		t.Errorf("%q is not reported", text)
+	}
+	for _, text := range unexpected {
+		t.Errorf("%q is reported", text)
+	}
+}
+
+// difference returns the elements of a that are not in b
+func difference(a []string, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	var diff []string
+	for _, s := range a {
+		if !in[s] {
+			diff = append(diff, s)
+		}
+	}
+	return diff
+}
+
+func TestInternationalComponent(t *testing.T) {
+	assertTexts(t, hardcodedTexts(t, "../../test-data/src/components/InternationalComponent.tsx"), []string{
+		"ようこそ",
+		"保存",
+		"この操作は元に戻せません。",
+		"メールアドレスを入力",
+		"欢迎使用我们的应用",
+		"加载中…",
+		"저장하기",
+		"Добро пожаловать",
+		"Сохранить",
+		"Ваши изменения были сохранены.",
+		"مرحبا بكم في تطبيقنا",
+		"حفظ التغييرات",
+		"ברוכים הבאים לאפליקציה שלנו",
+	})
+}
@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Suppression directives start a // or /* */ comment, outside string literals:
+//
+//	// next-intl-analyzer-disable-next-line hardcoded-string
+//	{/* next-intl-analyzer-ignore */}
+//	// next-intl-analyzer-disable-line undeclared-key
+//	/* next-intl-analyzer-disable hardcoded-string */
+//
+// Rule IDs are separated by commas or spaces; without rule IDs a directive
+// applies to every rule. Text after "--" is a free-form reason.
+const (
+	DirectiveDisableNextLine = "next-intl-analyzer-disable-next-line"
+	DirectiveDisableLine     = "next-intl-analyzer-disable-line"
+	DirectiveDisable         = "next-intl-analyzer-disable"
+	DirectiveIgnore          = "next-intl-analyzer-ignore"
+)
+
+// directivePattern matches a comment starting with a directive name, which
+// ends at the first space
+var directivePattern = regexp.MustCompile(`^\s*(next-intl-analyzer-\S*)(.*)$`)
+
+// isDirective reports whether name is one of the Directive constants
+func isDirective(name string) bool {
+	switch name {
+	case DirectiveDisableNextLine, DirectiveDisableLine, DirectiveDisable, DirectiveIgnore:
+		return true
+	}
+	return false
+}
+
+// Suppression is an inline directive silencing issues in a source file
+type Suppression struct {
+	File      string
+	Line      int    // line of the comment
+	Directive string // one of the Directive constants
+	Rules     []string
+	// FromLine and ToLine delimit the covered lines; both are 0 when the
+	// directive covers the whole file
+	FromLine int
+	ToLine   int
+}
+
+// Covers reports whether the suppression silences rule on the given line
+func (s Suppression) Covers(rule string, line int) bool {
+	if s.FromLine != 0 && (line < s.FromLine || line > s.ToLine) {
+		return false
+	}
+	if len(s.Rules) == 0 {
+		return true
+	}
+	for _, r := range s.Rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// Text returns the directive as written, without the reason
+func (s Suppression) Text() string {
+	if len(s.Rules) == 0 {
+		return s.Directive
+	}
+	return s.Directive + " " + strings.Join(s.Rules, ", ")
+}
+
+// parseSuppression returns the suppression declared by comment, the text of
+// a comment starting on line lineNum without its delimiters, if any. lineNum
+// is 1-based. A comment naming no known directive, such as a misspelled one,
+// is returned as well; see isDirective.
+func parseSuppression(filePath string, comment string, lineNum int) (Suppression, bool) {
+	match := directivePattern.FindStringSubmatch(comment)
+	if match == nil {
+		return Suppression{}, false
+	}
+
+	suppression := Suppression{File: filePath, Line: lineNum, Directive: match[1]}
+
+	rules := match[2]
+	if end := strings.Index(rules, "--"); end >= 0 {
+		rules = rules[:end]
+	}
+	for _, rule := range strings.FieldsFunc(rules, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		suppression.Rules = append(suppression.Rules, rule)
+	}
+
+	switch suppression.Directive {
+	case DirectiveDisableNextLine:
+		suppression.FromLine, suppression.ToLine = lineNum+1, lineNum+1
+	case DirectiveDisableLine:
+		suppression.FromLine, suppression.ToLine = lineNum, lineNum
+	case DirectiveIgnore:
+		// JSX has no line comments, so {/* ignore */} applies to its own line
+		// and to the element on the following line
+		suppression.FromLine, suppression.ToLine = lineNum, lineNum+1
+	}
+	return suppression, true
+}
+
+// applySuppressions removes the issues silenced by suppression comments and
+// reports the suppressions that silenced nothing as unused-suppression
+// issues. It runs before rule severities are applied, so a suppression of a
+// rule that is turned off still counts as used.
+func applySuppressions(results *AnalysisResult, suppressions []Suppression) {
+	if len(suppressions) == 0 {
+		return
+	}
+
+	byFile := make(map[string][]int)
+	for i, suppression := range suppressions {
+		byFile[suppression.File] = append(byFile[suppression.File], i)
+	}
+	used := make([]bool, len(suppressions))
+
+	suppressed := func(rule string) func(Translation) bool {
+		return func(translation Translation) bool {
+			matched := false
+			for _, i := range byFile[translation.File] {
+				if suppressions[i].Covers(rule, translation.Line) {
+					used[i] = true
+					matched = true
+				}
+			}
+			return matched
+		}
+	}
+
+	results.UndeclaredTranslations = removeTranslations(results.UndeclaredTranslations, suppressed(RuleUndeclaredKey))
+	results.HardcodedStrings = removeTranslations(results.HardcodedStrings, suppressed(RuleHardcodedString))
+	for locale, localeResult := range results.LocaleResults {
+		inLocale := func(translation Translation) bool { return translation.Locale != locale }
+		localeResult.UndeclaredTranslations = removeTranslations(results.UndeclaredTranslations, inLocale)
+	}
+
+	for i, suppression := range suppressions {
+		if used[i] {
+			continue
+		}
+		results.UnusedSuppressions = append(results.UnusedSuppressions, Translation{
+			Key:  suppression.Text(),
+			File: suppression.File,
+			Line: suppression.Line,
+			Type: TypeSuppression,
+		})
+	}
+	sortTranslations(results.UnusedSuppressions)
+}
+
+// validateSuppression returns an error naming the unknown directive of a
+// suppression, or else the first unknown rule ID it uses
+func validateSuppression(suppression Suppression) error {
+	if !isDirective(suppression.Directive) {
+		return fmt.Errorf("%s:%d: unknown directive %q", suppression.File, suppression.Line, suppression.Directive)
+	}
+	for _, rule := range suppression.Rules {
+		if _, ok := LookupRule(rule); !ok {
+			return fmt.Errorf("%s:%d: unknown rule %q in %s", suppression.File, suppression.Line, rule, suppression.Directive)
+		}
+	}
+	return nil
+}
+
+// commentScanner finds the comments of source lines, read one after the
+// other. Comment delimiters within string literals are not comments.
+type commentScanner struct {
+	// inBlock is set while a /* */ comment spans lines, inTemplate while a
+	// template literal does
+	inBlock    bool
+	inTemplate bool
+}
+
+// comments returns the text of the comments starting on line, without their
+// delimiters. A block comment continued on the next lines is cut at the end
+// of line.
+func (c *commentScanner) comments(line string) []string {
+	var comments []string
+	for i := 0; i < len(line); i++ {
+		switch {
+		case c.inBlock:
+			end := strings.Index(line[i:], "*/")
+			if end < 0 {
+				return comments
+			}
+			c.inBlock = false
+			i += end + 1
+		case c.inTemplate:
+			i = skipLiteral(line, i, '`')
+			c.inTemplate = i >= len(line)
+		case line[i] == '"' || line[i] == '\'':
+			// Quoted strings end with the line, as do unbalanced quotes
+			// in JSX text, such as "Don't"
+			i = skipLiteral(line, i+1, line[i])
+		case line[i] == '`':
+			i = skipLiteral(line, i+1, '`')
+			c.inTemplate = i >= len(line)
+		case strings.HasPrefix(line[i:], "//"):
+			return append(comments, line[i+2:])
+		case strings.HasPrefix(line[i:], "/*"):
+			end := strings.Index(line[i+2:], "*/")
+			if end < 0 {
+				c.inBlock = true
+				return append(comments, line[i+2:])
+			}
+			comments = append(comments, line[i+2:i+2+end])
+			i += 2 + end + 1
+		}
+	}
+	return comments
+}
+
+// skipLiteral returns the index of the quote closing the string literal
+// starting at start, or len(line) when it does not end on line
+func skipLiteral(line string, start int, quote byte) int {
+	for i := start; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return len(line)
+}
@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSuppressions(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []Suppression
+	}{
+		{
+			name:   "disable next line",
+			source: "// next-intl-analyzer-disable-next-line hardcoded-string\n<p>Text</p>",
+			want:   []Suppression{{Line: 1, Directive: DirectiveDisableNextLine, Rules: []string{RuleHardcodedString}, FromLine: 2, ToLine: 2}},
+		},
+		{
+			name:   "disable line",
+			source: "\n<p>{t('missing')}</p> // next-intl-analyzer-disable-line undeclared-key",
+			want:   []Suppression{{Line: 2, Directive: DirectiveDisableLine, Rules: []string{RuleUndeclaredKey}, FromLine: 2, ToLine: 2}},
+		},
+		{
+			name:   "disable file",
+			source: "/* next-intl-analyzer-disable hardcoded-string */",
+			want:   []Suppression{{Line: 1, Directive: DirectiveDisable, Rules: []string{RuleHardcodedString}}},
+		},
+		{
+			name:   "ignore in JSX",
+			source: "<div>\n  {/* next-intl-analyzer-ignore */}\n  <p>Text</p>",
+			want:   []Suppression{{Line: 2, Directive: DirectiveIgnore, FromLine: 2, ToLine: 3}},
+		},
+		{
+			name:   "rule list and reason",
+			source: "// next-intl-analyzer-disable-next-line hardcoded-string, undeclared-key unused-suppression -- legacy page",
+			want: []Suppression{{Line: 1, Directive: DirectiveDisableNextLine,
+				Rules: []string{RuleHardcodedString, RuleUndeclaredKey, RuleUnusedSuppression}, FromLine: 2, ToLine: 2}},
+		},
+		{
+			name:   "block comment over several lines",
+			source: "/* next-intl-analyzer-disable hardcoded-string\n   next-intl-analyzer-ignore */",
+			want:   []Suppression{{Line: 1, Directive: DirectiveDisable, Rules: []string{RuleHardcodedString}}},
+		},
+		{
+			name:   "directive after code",
+			source: `const label = "Save"; /* next-intl-analyzer-disable-line */`,
+			want:   []Suppression{{Line: 1, Directive: DirectiveDisableLine, FromLine: 1, ToLine: 1}},
+		},
+		// Directives must start a comment
+		{name: "in a string", source: `const url = "http://example.com/* next-intl-analyzer-disable */";`},
+		{name: "in a single-quoted string", source: `const s = '// next-intl-analyzer-disable';`},
+		{name: "in a template literal", source: "const s = `\n// next-intl-analyzer-disable\n`;"},
+		{name: "within a comment", source: "// see next-intl-analyzer-disable in the docs"},
+		{name: "after a comment", source: "const s = '//'; // next-intl-analyzer-disable-line", want: []Suppression{{Line: 1, Directive: DirectiveDisableLine, FromLine: 1, ToLine: 1}}},
+	}
+	p := NewTranslationParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.want {
+				tt.want[i].File = "page.tsx"
+			}
+			source := p.ParseSource("page.tsx", []byte(tt.source))
+			if len(source.UnknownDirectives) > 0 {
+				t.Errorf("unknown directives %v", source.UnknownDirectives)
+			}
+			if len(source.Suppressions) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(source.Suppressions, tt.want) {
+				t.Errorf("suppressions = %+v, want %+v", source.Suppressions, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnknownDirectives(t *testing.T) {
+	tests := []struct {
+		source    string
+		directive string
+	}{
+		{"// next-intl-analyzer-disable-nextline", "next-intl-analyzer-disable-nextline"},
+		{"// next-intl-analyzer-disable-lines hardcoded-string", "next-intl-analyzer-disable-lines"},
+		{"{/* next-intl-analyzer-ignored */}", "next-intl-analyzer-ignored"},
+	}
+	p := NewTranslationParser()
+	for _, tt := range tests {
+		source := p.ParseSource("page.tsx", []byte(tt.source))
+		if len(source.Suppressions) > 0 {
+			t.Errorf("%q: suppressions = %+v, want none", tt.source, source.Suppressions)
+		}
+		if len(source.UnknownDirectives) != 1 {
+			t.Errorf("%q: unknown directives = %+v, want one", tt.source, source.UnknownDirectives)
+			continue
+		}
+		unknown := source.UnknownDirectives[0]
+		if unknown.Directive != tt.directive {
+			t.Errorf("%q: directive = %q, want %q", tt.source, unknown.Directive, tt.directive)
+		}
+		if err := validateSuppression(unknown); err == nil {
+			t.Errorf("%q: no error for an unknown directive", tt.source)
+		}
+	}
+}
+
+func TestSuppressionCovers(t *testing.T) {
+	tests := []struct {
+		name        string
+		suppression Suppression
+		rule        string
+		line        int
+		want        bool
+	}{
+		{"next line", Suppression{FromLine: 5, ToLine: 5}, RuleHardcodedString, 5, true},
+		{"before the range", Suppression{FromLine: 5, ToLine: 5}, RuleHardcodedString, 4, false},
+		{"after the range", Suppression{FromLine: 5, ToLine: 5}, RuleHardcodedString, 6, false},
+		{"ignore, own line", Suppression{FromLine: 5, ToLine: 6}, RuleHardcodedString, 5, true},
+		{"ignore, element line", Suppression{FromLine: 5, ToLine: 6}, RuleHardcodedString, 6, true},
+		{"whole file", Suppression{}, RuleUndeclaredKey, 1000, true},
+		{"listed rule", Suppression{Rules: []string{RuleUndeclaredKey, RuleHardcodedString}}, RuleHardcodedString, 1, true},
+		{"other rule", Suppression{Rules: []string{RuleUndeclaredKey}}, RuleHardcodedString, 1, false},
+	}
+	for _, tt := range tests {
+		if got := tt.suppression.Covers(tt.rule, tt.line); got != tt.want {
+			t.Errorf("%s: Covers(%s, %d) = %v, want %v", tt.name, tt.rule, tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WorkspaceReport describes the analysis of a workspace, see Config.Projects.
+// The issues of the AnalysisResult it belongs to are those of every project;
+// a key of a shared message directory is only unused when no project sharing
+// it uses the key.
+type WorkspaceReport struct {
+	// Projects holds the results of every project, in configuration order
+	Projects []ProjectResult
+	// SharedKeys lists the keys of shared message directories that a single
+	// project uses, sorted by directory and key
+	SharedKeys []SharedKey
+
+	// root is the workspace directory that file paths are relative to
+	root string
+}
+
+// ProjectResult is the analysis of one project of a workspace. Results are
+// those of the project alone, before keys used by other projects sharing its
+// messages are taken into account.
+type ProjectResult struct {
+	ProjectConfig
+	Results *AnalysisResult
+}
+
+// SharedKey is a key of a message directory shared by several projects that
+// only one of them uses
+type SharedKey struct {
+	Key string
+	// Dir is the shared message directory, as configured
+	Dir    string
+	UsedBy string
+}
+
+// Issues returns the issues of results in the files of the named project:
+// its source files and its message files, shared ones included. Unlike
+// ProjectResult.Results, they reflect what was done to results after the
+// analysis, such as applying a baseline.
+func (w *WorkspaceReport) Issues(results *AnalysisResult, project string) []Issue {
+	var dirs []string
+	for _, candidate := range w.Projects {
+		if candidate.Name == project {
+			dirs = append(cleanRoots(candidate.Sources), cleanRoots(candidate.Messages)...)
+		}
+	}
+
+	var issues []Issue
+	for _, issue := range results.Issues() {
+		if inRoots(relativePath(w.root, issue.File), dirs) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// isWorkspace reports whether Analyze analyzes the projects of the
+// configuration. Analyzers limited to roots analyze a single project.
+func (a *Analyzer) isWorkspace() bool {
+	return len(a.config.Projects) > 0 && a.sourceRoots == nil && a.messageRoots == nil
+}
+
+// projectAnalyzer returns an analyzer of one project of the workspace, which
+// shares the file system, cache and configuration of a
+func (a *Analyzer) projectAnalyzer(project ProjectConfig, workers int) *Analyzer {
+	return &Analyzer{
+		projectPath:  a.projectPath,
+		fsys:         a.fsys,
+		files:        a.files,
+		sourceRoots:  cleanRoots(project.Sources),
+		messageRoots: cleanRoots(project.Messages),
+		results:      newAnalysisResult(),
+		concurrency:  workers,
+		cache:        a.cache,
+		config:       a.config,
+	}
+}
+
+// analyzeWorkspace analyzes the projects of the configuration concurrently
+// and merges their results
+func (a *Analyzer) analyzeWorkspace(ctx context.Context) (*AnalysisResult, error) {
+	a.results = newAnalysisResult()
+	projects := a.config.Projects
+
+	// The projects share the workers, so that a workspace does not parse
+	// more files at once than a single project
+	workers := a.concurrency / len(projects)
+	if workers < 1 {
+		workers = 1
+	}
+
+	analyzers := make([]*Analyzer, len(projects))
+	results := make([]*AnalysisResult, len(projects))
+	errs := make([]error, len(projects))
+	done := make(chan int)
+	for i, project := range projects {
+		analyzers[i] = a.projectAnalyzer(project, workers)
+		go func(i int) {
+			results[i], errs[i] = analyzers[i].Analyze(ctx)
+			done <- i
+		}(i)
+	}
+
+	// Progress is reported from this goroutine only, as in parseSourceFiles
+	a.reportProgress("Analyzing projects", 0, len(projects))
+	for completed := 1; completed <= len(projects); completed++ {
+		<-done
+		a.reportProgress("Analyzing projects", completed, len(projects))
+	}
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("project %s: %w", projects[i].Name, err)
+		}
+	}
+
+	a.reportProgress("Merging project results", 0, 1)
+	report := &WorkspaceReport{root: a.projectPath}
+	for i, project := range projects {
+		report.Projects = append(report.Projects, ProjectResult{ProjectConfig: project, Results: results[i]})
+	}
+	if err := a.mergeProjectResults(report, analyzers); err != nil {
+		return nil, err
+	}
+	a.results.Workspace = report
+
+	a.reportProgress("Complete", 1, 1)
+	return a.results, nil
+}
+
+// mergeProjectResults fills a.results with the issues of every project and
+// report with the shared keys used by a single project
+func (a *Analyzer) mergeProjectResults(report *WorkspaceReport, analyzers []*Analyzer) error {
+	translationID := func(translation Translation) string {
+		return translation.Locale + "\x00" + translation.File + "\x00" + translation.Key
+	}
+
+	// Which projects declare and use every key of every message file. Use
+	// is taken from the source files, as rules, suppressions and baselines
+	// may have hidden unused keys from the results.
+	declared := make(map[string]Translation)
+	usedBy := make(map[string][]string)
+	namespaces := make(map[string]bool)
+	for i, project := range report.Projects {
+		messages, err := analyzers[i].Messages()
+		if err != nil {
+			return fmt.Errorf("project %s: %w", project.Name, err)
+		}
+		for _, translations := range messages {
+			for key, translation := range translations {
+				id := translationID(translation)
+				declared[id] = translation
+				if analyzers[i].usage.uses(key) {
+					usedBy[id] = appendUnique(usedBy[id], project.Name)
+				}
+				for j := strings.LastIndex(key, "."); j > 0; j = strings.LastIndex(key[:j], ".") {
+					namespaces[key[:j]] = true
+				}
+			}
+		}
+	}
+
+	merged := newAnalysisResult()
+	seen := make(map[string]bool)
+	add := func(list []Translation, kind string, translation Translation) []Translation {
+		id := kind + "\x00" + translationID(translation) + "\x00" + strconv.Itoa(translation.Line)
+		if seen[id] {
+			return list
+		}
+		seen[id] = true
+		return append(list, translation)
+	}
+	for _, project := range report.Projects {
+		results := project.Results
+		for _, translation := range results.UnusedTranslations {
+			if len(usedBy[translationID(translation)]) == 0 {
+				merged.UnusedTranslations = add(merged.UnusedTranslations, RuleUnusedKey, translation)
+			}
+		}
+		for _, translation := range results.UndeclaredTranslations {
+			merged.UndeclaredTranslations = add(merged.UndeclaredTranslations, RuleUndeclaredKey, translation)
+		}
+		for _, translation := range results.HardcodedStrings {
+			merged.HardcodedStrings = add(merged.HardcodedStrings, RuleHardcodedString, translation)
+		}
+		for _, translation := range results.MissingTranslations {
+			merged.MissingTranslations = add(merged.MissingTranslations, RuleMissingTranslation, translation)
+		}
+		for _, translation := range results.UnusedSuppressions {
+			merged.UnusedSuppressions = add(merged.UnusedSuppressions, RuleUnusedSuppression, translation)
+		}
+		for _, diagnostic := range results.Diagnostics {
+			id := "diagnostic\x00" + diagnostic.File + "\x00" + strconv.Itoa(diagnostic.Line) + "\x00" + diagnostic.Message
+			if !seen[id] {
+				seen[id] = true
+				merged.Diagnostics = append(merged.Diagnostics, diagnostic)
+			}
+		}
+		for locale := range results.LocaleResults {
+			if _, ok := merged.LocaleResults[locale]; !ok {
+				merged.LocaleResults[locale] = &LocaleAnalysisResult{
+					Locale:                 locale,
+					UnusedTranslations:     make([]Translation, 0),
+					UndeclaredTranslations: make([]Translation, 0),
+					HardcodedStrings:       make([]Translation, 0),
+				}
+			}
+		}
+	}
+
+	for _, list := range [][]Translation{merged.UnusedTranslations, merged.UndeclaredTranslations, merged.HardcodedStrings, merged.MissingTranslations, merged.UnusedSuppressions} {
+		sortTranslations(list)
+	}
+	for _, translation := range merged.UnusedTranslations {
+		localeResult := merged.LocaleResults[translation.Locale]
+		localeResult.UnusedTranslations = append(localeResult.UnusedTranslations, translation)
+	}
+	for _, translation := range merged.UndeclaredTranslations {
+		localeResult := merged.LocaleResults[translation.Locale]
+		localeResult.UndeclaredTranslations = append(localeResult.UndeclaredTranslations, translation)
+	}
+	// A key shared by several projects counts once, used or not
+	for id, translation := range declared {
+		if localeResult, ok := merged.LocaleResults[translation.Locale]; ok {
+			localeResult.TotalTranslations++
+			if len(usedBy[id]) > 0 {
+				localeResult.UsedTranslations++
+			}
+		}
+	}
+	for _, localeResult := range merged.LocaleResults {
+		merged.TotalTranslations += localeResult.TotalTranslations
+		merged.UsedTranslations += localeResult.UsedTranslations
+	}
+
+	// Keys of directories shared by several projects, used by one of them
+	sharing := make(map[string][]string)
+	for _, project := range report.Projects {
+		for _, dir := range cleanRoots(project.Messages) {
+			sharing[dir] = appendUnique(sharing[dir], project.Name)
+		}
+	}
+	sharedKeys := make(map[SharedKey]bool)
+	for id, translation := range declared {
+		if namespaces[translation.Key] || len(usedBy[id]) != 1 {
+			continue
+		}
+		name := relativePath(a.projectPath, translation.File)
+		for dir, projects := range sharing {
+			if len(projects) > 1 && inRoots(name, []string{dir}) {
+				sharedKeys[SharedKey{Key: translation.Key, Dir: dir, UsedBy: usedBy[id][0]}] = true
+			}
+		}
+	}
+	for key := range sharedKeys {
+		report.SharedKeys = append(report.SharedKeys, key)
+	}
+	sort.Slice(report.SharedKeys, func(i, j int) bool {
+		x, y := report.SharedKeys[i], report.SharedKeys[j]
+		if x.Dir != y.Dir {
+			return x.Dir < y.Dir
+		}
+		return x.Key < y.Key
+	})
+
+	a.results = merged
+	return nil
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
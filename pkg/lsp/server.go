@@ -0,0 +1,847 @@
+// Package lsp implements a Language Server Protocol server on top of the
+// analyzer package. It publishes undeclared-key and hardcoded-string
+// diagnostics for open JSX/TSX documents, completes message keys inside
+// translation calls, and resolves calls to their declarations in the message
+// files.
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+const diagnosticSource = "next-intl-analyzer"
+
+var (
+	// A translation call whose key literal is still being typed, anchored at
+	// the cursor. Example: t('button.sa
+	completionContextPattern = regexp.MustCompile(`(\w+)(?:\.(?:rich|markup|raw|has))?\s*\(\s*['"]([^'"]*)$`)
+
+	// A complete translation call. Example: t('button.save')
+	callPattern = regexp.MustCompile(`(\w+)(?:\.(?:rich|markup|raw|has))?\s*\(\s*['"]([^'"]+)['"]`)
+)
+
+// Server is a Language Server Protocol server speaking JSON-RPC over a
+// reader and a writer, typically stdin and stdout.
+type Server struct {
+	conn   *conn
+	parser *analyzer.TranslationParser
+
+	root   string
+	config *analyzer.Config
+
+	// documents holds the content of open documents by path
+	documents map[string]string
+	// overlay is the workspace as the editor sees it: the files on disk with
+	// the unsaved content of open documents on top
+	overlay *analyzer.Overlay
+	// messages holds the keys declared in every message file by path
+	messages map[string]map[string]analyzer.Translation
+	// projects are the projects of the workspace, or the workspace itself
+	// when the configuration defines none
+	projects []*project
+
+	// watchFiles is set when the client can watch files for the server
+	watchFiles bool
+	// lastID is the ID of the last request sent to the client
+	lastID int
+
+	// shutdown is set by the shutdown request, which the exit notification
+	// must follow
+	shutdown bool
+}
+
+// ErrExitWithoutShutdown is returned by Run when the client sends the exit
+// notification without a shutdown request first. The LSP specification asks
+// the server to exit with status 1 then, and 0 otherwise.
+var ErrExitWithoutShutdown = errors.New("exit notification received before shutdown")
+
+// NewServer returns a server reading requests from r and writing responses
+// and notifications to w.
+func NewServer(r io.Reader, w io.Writer) *Server {
+	return &Server{
+		conn:      newConn(r, w),
+		parser:    analyzer.NewTranslationParser(),
+		root:      ".",
+		config:    analyzer.DefaultConfig(),
+		documents: make(map[string]string),
+		overlay:   analyzer.NewOverlay(analyzer.DirFS(".")),
+		messages:  make(map[string]map[string]analyzer.Translation),
+	}
+}
+
+// Run serves requests until the client sends the exit notification, the
+// input is closed or ctx is cancelled. An exit before shutdown returns
+// ErrExitWithoutShutdown.
+func (s *Server) Run(ctx context.Context) error {
+	type readResult struct {
+		msg *message
+		err error
+	}
+	incoming := make(chan readResult)
+
+	go func() {
+		for {
+			msg, err := s.conn.read()
+			select {
+			case incoming <- readResult{msg, err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				var rpcErr *responseError
+				if !errors.As(err, &rpcErr) {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		var result readResult
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case result = <-incoming:
+		}
+
+		if result.err != nil {
+			var rpcErr *responseError
+			if errors.As(result.err, &rpcErr) {
+				s.conn.write(&message{ID: nullID(), Error: rpcErr})
+				continue
+			}
+			if errors.Is(result.err, io.EOF) {
+				return nil
+			}
+			return result.err
+		}
+
+		if result.msg.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		}
+		s.handle(result.msg)
+	}
+}
+
+func nullID() *json.RawMessage {
+	id := json.RawMessage("null")
+	return &id
+}
+
+func (s *Server) handle(msg *message) {
+	// Responses to the requests of the server, such as
+	// client/registerCapability, need no handling
+	if msg.Method == "" {
+		return
+	}
+
+	var result interface{}
+	var err error
+
+	switch msg.Method {
+	case "initialize":
+		result, err = s.initialize(msg.Params)
+	case "initialized":
+		if s.watchFiles {
+			s.registerWatchers()
+		}
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			s.documentChanged(uriToPath(params.TextDocument.URI), params.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil && len(params.ContentChanges) > 0 {
+			// The server only asks for full document synchronisation
+			text := params.ContentChanges[len(params.ContentChanges)-1].Text
+			s.documentChanged(uriToPath(params.TextDocument.URI), text)
+		}
+	case "textDocument/didSave":
+		var params didSaveParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			path := uriToPath(params.TextDocument.URI)
+			if text, open := s.documents[path]; open {
+				s.documentChanged(path, text)
+			}
+		}
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			s.documentClosed(uriToPath(params.TextDocument.URI))
+		}
+	case "workspace/didChangeWatchedFiles":
+		// Files changed outside the editor, such as by a checkout
+		s.loadConfig()
+		s.loadMessages()
+		s.publishAll()
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.completion(uriToPath(params.TextDocument.URI), params.Position)
+		}
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.definition(uriToPath(params.TextDocument.URI), params.Position)
+		}
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			if h := s.hover(uriToPath(params.TextDocument.URI), params.Position); h != nil {
+				result = h
+			}
+		}
+	default:
+		if msg.ID != nil {
+			s.conn.write(&message{ID: msg.ID, Error: &responseError{
+				Code:    codeMethodNotFound,
+				Message: fmt.Sprintf("method not supported: %s", msg.Method),
+			}})
+		}
+		return
+	}
+
+	// Notifications have no ID and get no response
+	if msg.ID == nil {
+		return
+	}
+	if err != nil {
+		s.conn.write(&message{ID: msg.ID, Error: &responseError{Code: codeInvalidParams, Message: err.Error()}})
+		return
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	s.conn.write(&message{ID: msg.ID, Result: result})
+}
+
+func (s *Server) initialize(params json.RawMessage) (interface{}, error) {
+	var p initializeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.RootURI != "":
+		s.root = uriToPath(p.RootURI)
+	case p.RootPath != "":
+		s.root = p.RootPath
+	}
+	s.watchFiles = p.Capabilities.Workspace.DidChangeWatchedFiles.DynamicRegistration
+	s.overlay = analyzer.NewOverlay(analyzer.DirFS(s.root))
+	for path, text := range s.documents {
+		if name, ok := s.fsName(path); ok {
+			s.overlay.Set(name, []byte(text))
+		}
+	}
+
+	s.loadConfig()
+	s.loadMessages()
+
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync": map[string]interface{}{
+				"openClose": true,
+				"change":    1, // full document
+				"save":      map[string]bool{"includeText": false},
+			},
+			"completionProvider": map[string]interface{}{
+				"triggerCharacters": []string{"'", "\"", "."},
+			},
+			"definitionProvider": true,
+			"hoverProvider":      true,
+		},
+		"serverInfo": map[string]string{
+			"name":    diagnosticSource,
+			"version": analyzer.Version,
+		},
+	}, nil
+}
+
+// registerWatchers asks the client to report changes of the files the
+// server reads from disk: message files, the configuration and ignore files,
+// and source files being added or removed
+func (s *Server) registerWatchers() {
+	watchers := []fileSystemWatcher{
+		{GlobPattern: "**/messages/*.json"},
+		{GlobPattern: "**/" + analyzer.ConfigFileName},
+		{GlobPattern: "**/.gitignore"},
+		{GlobPattern: "**/*.{jsx,tsx}", Kind: watchCreate | watchDelete},
+	}
+	s.lastID++
+	id := json.RawMessage(strconv.Itoa(s.lastID))
+	s.conn.write(&message{ID: &id, Method: "client/registerCapability", Params: mustMarshal(registrationParams{
+		Registrations: []registration{{
+			ID:              "watched-files",
+			Method:          "workspace/didChangeWatchedFiles",
+			RegisterOptions: didChangeWatchedFilesRegistrationOptions{Watchers: watchers},
+		}},
+	})})
+}
+
+// loadConfig (re)reads the configuration of the workspace. The default
+// configuration is kept when there is none, and the last one when it cannot
+// be read.
+func (s *Server) loadConfig() {
+	config, err := analyzer.LoadProjectConfigFS(s.overlay)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "next-intl-analyzer: %v\n", err)
+		return
+	}
+	s.config = config
+	s.parser = analyzer.NewTranslationParser(config.ParserOptions()...)
+}
+
+// project is a set of source files and the message files they use. Like
+// analyze, the server only reports the files of a project, and looks keys up
+// in its own messages.
+type project struct {
+	sources  map[string]bool
+	messages map[string]bool
+}
+
+// loadMessages (re)reads every message file of the workspace, taking the
+// content of message files that are open in the editor from their buffers,
+// and finds the files of every project.
+func (s *Server) loadMessages() {
+	options := []analyzer.Option{analyzer.WithFS(s.overlay), analyzer.WithDir(s.root), analyzer.WithConfig(s.config)}
+	var analyzers []*analyzer.Analyzer
+	if len(s.config.Projects) == 0 {
+		workspace, err := analyzer.New(options...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "next-intl-analyzer: %v\n", err)
+			return
+		}
+		analyzers = append(analyzers, workspace)
+	}
+	for _, config := range s.config.Projects {
+		projectAnalyzer, err := analyzer.New(append(options,
+			analyzer.WithSourceRoots(config.Sources...), analyzer.WithMessageRoots(config.Messages...))...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "next-intl-analyzer: %v\n", err)
+			return
+		}
+		analyzers = append(analyzers, projectAnalyzer)
+	}
+
+	s.messages = make(map[string]map[string]analyzer.Translation)
+	s.projects = nil
+	for _, projectAnalyzer := range analyzers {
+		localeFiles, err := projectAnalyzer.TranslationFiles()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "next-intl-analyzer: %v\n", err)
+			continue
+		}
+		sourceFiles, err := projectAnalyzer.SourceFiles()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "next-intl-analyzer: %v\n", err)
+			continue
+		}
+
+		p := &project{sources: make(map[string]bool), messages: make(map[string]bool)}
+		for _, file := range sourceFiles {
+			p.sources[file] = true
+		}
+		for _, files := range localeFiles {
+			for _, file := range files {
+				p.messages[file] = true
+				if _, loaded := s.messages[file]; loaded {
+					continue
+				}
+				content, err := s.readFile(file)
+				if err != nil {
+					continue
+				}
+				s.parseMessages(file, string(content))
+			}
+		}
+		s.projects = append(s.projects, p)
+	}
+}
+
+// analyzed reports whether analyze reads the source file path, which is not
+// excluded, ignored or outside every project
+func (s *Server) analyzed(path string) bool {
+	for _, p := range s.projects {
+		if p.sources[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleMessages reports whether the keys of the message file are visible
+// from the source file path: the file belongs to a project of path. Files
+// that are not analyzed see every message file.
+func (s *Server) visibleMessages(path string, file string) bool {
+	if !s.analyzed(path) {
+		return true
+	}
+	for _, p := range s.projects {
+		if p.sources[path] && p.messages[file] {
+			return true
+		}
+	}
+	return false
+}
+
+// fsName returns the name of path in the workspace file system, or false
+// when path is outside the workspace
+func (s *Server) fsName(path string) (string, bool) {
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil {
+		return "", false
+	}
+	name := filepath.ToSlash(rel)
+	return name, fs.ValidPath(name)
+}
+
+// readFile returns the content of path, taken from the editor buffer when
+// the document is open
+func (s *Server) readFile(path string) ([]byte, error) {
+	if name, ok := s.fsName(path); ok {
+		return fs.ReadFile(s.overlay, name)
+	}
+	if text, open := s.documents[path]; open {
+		return []byte(text), nil
+	}
+	return os.ReadFile(path)
+}
+
+func (s *Server) parseMessages(path string, text string) {
+	declared, err := s.parser.ParseTranslation(path, []byte(text))
+	if err != nil {
+		// Keep the last valid declarations while the file is being edited
+		return
+	}
+	s.messages[path] = declared
+}
+
+func (s *Server) documentChanged(path string, text string) {
+	_, wasOpen := s.documents[path]
+	s.documents[path] = text
+	if name, ok := s.fsName(path); ok {
+		s.overlay.Set(name, []byte(text))
+	}
+
+	// A document opened before it is written to disk is a new file of the
+	// workspace
+	if !wasOpen && s.isNew(path) {
+		s.loadMessages()
+	}
+
+	switch {
+	case analyzer.LocaleFromPath(path) != "":
+		s.parseMessages(path, text)
+		s.publishAll()
+	case analyzer.IsSourceFile(path):
+		s.publish(path)
+	}
+}
+
+// isNew reports whether path is a message or source file that does not
+// exist on disk
+func (s *Server) isNew(path string) bool {
+	if analyzer.LocaleFromPath(path) == "" && !analyzer.IsSourceFile(path) {
+		return false
+	}
+	_, err := os.Stat(path)
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+func (s *Server) documentClosed(path string) {
+	delete(s.documents, path)
+	if name, ok := s.fsName(path); ok {
+		s.overlay.Delete(name)
+	}
+
+	switch {
+	case analyzer.LocaleFromPath(path) != "":
+		// Fall back to the content on disk
+		delete(s.messages, path)
+		if content, err := s.readFile(path); err == nil {
+			s.parseMessages(path, string(content))
+		}
+		s.publishAll()
+	case analyzer.IsSourceFile(path):
+		s.conn.write(&message{Method: "textDocument/publishDiagnostics", Params: mustMarshal(publishDiagnosticsParams{
+			URI:         pathToURI(path),
+			Diagnostics: []Diagnostic{},
+		})})
+	}
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// locales returns the declared keys visible from the source file path merged
+// per locale, and the sorted list of locales.
+func (s *Server) locales(path string) (map[string]map[string]analyzer.Translation, []string) {
+	files := make([]string, 0, len(s.messages))
+	for file := range s.messages {
+		if s.visibleMessages(path, file) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	byLocale := make(map[string]map[string]analyzer.Translation)
+	for _, file := range files {
+		locale := analyzer.LocaleFromPath(file)
+		if byLocale[locale] == nil {
+			byLocale[locale] = make(map[string]analyzer.Translation)
+		}
+		for key, translation := range s.messages[file] {
+			translation.Locale = locale
+			byLocale[locale][key] = translation
+		}
+	}
+
+	locales := make([]string, 0, len(byLocale))
+	for locale := range byLocale {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return byLocale, locales
+}
+
+func (s *Server) publishAll() {
+	paths := make([]string, 0, len(s.documents))
+	for path := range s.documents {
+		if analyzer.IsSourceFile(path) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		s.publish(path)
+	}
+}
+
+func (s *Server) publish(path string) {
+	s.conn.write(&message{Method: "textDocument/publishDiagnostics", Params: mustMarshal(publishDiagnosticsParams{
+		URI:         pathToURI(path),
+		Diagnostics: s.diagnostics(path),
+	})})
+}
+
+// diagnostics reports undeclared keys and hardcoded strings in an open
+// source document
+func (s *Server) diagnostics(path string) []Diagnostic {
+	// Like analyze, report nothing in excluded and ignored files
+	if !s.analyzed(path) {
+		return []Diagnostic{}
+	}
+	text := s.documents[path]
+	lines := strings.Split(text, "\n")
+	byLocale, locales := s.locales(path)
+
+	source := s.parser.ParseSource(path, []byte(text))
+	translations := make([]analyzer.Translation, 0, len(source.Translations))
+	for _, translation := range source.Translations {
+		translations = append(translations, translation)
+	}
+	suppressed := func(rule string, line int) bool {
+		for _, suppression := range source.Suppressions {
+			if suppression.Covers(rule, line) {
+				return true
+			}
+		}
+		return false
+	}
+	sort.Slice(translations, func(i, j int) bool {
+		if translations[i].Line != translations[j].Line {
+			return translations[i].Line < translations[j].Line
+		}
+		return translations[i].Key < translations[j].Key
+	})
+
+	relativePath := filepath.ToSlash(path)
+	if rel, err := filepath.Rel(s.root, path); err == nil {
+		relativePath = filepath.ToSlash(rel)
+	}
+	undeclaredSeverity := lspSeverity(s.config.SeverityFor(analyzer.RuleUndeclaredKey, relativePath))
+	hardcodedSeverity := lspSeverity(s.config.SeverityFor(analyzer.RuleHardcodedString, relativePath))
+
+	diagnostics := make([]Diagnostic, 0)
+	for _, translation := range translations {
+		if translation.Line < 1 || translation.Line > len(lines) {
+			continue
+		}
+		line := lines[translation.Line-1]
+
+		switch translation.Type {
+		case analyzer.TypeTranslationCall:
+			if undeclaredSeverity == 0 || suppressed(analyzer.RuleUndeclaredKey, translation.Line) {
+				continue
+			}
+			var missing []string
+			for _, locale := range locales {
+				if _, declared := byLocale[locale][translation.Key]; !declared {
+					missing = append(missing, locale)
+				}
+			}
+			if len(missing) == 0 {
+				continue
+			}
+			start, end := findKey(line, translation.Key)
+			diagnostics = append(diagnostics, Diagnostic{
+				Range:    lineRange(translation.Line-1, line, start, end),
+				Severity: undeclaredSeverity,
+				Code:     analyzer.RuleUndeclaredKey,
+				Source:   diagnosticSource,
+				Message:  fmt.Sprintf("Translation key %q is not declared in: %s", translation.Key, strings.Join(missing, ", ")),
+			})
+		case analyzer.TypeHardcodedString:
+			if hardcodedSeverity == 0 || translation.Confidence < s.config.ConfidenceThreshold() ||
+				suppressed(analyzer.RuleHardcodedString, translation.Line) {
+				continue
+			}
+			start := strings.Index(line, translation.Key)
+			end := start + len(translation.Key)
+			if start < 0 {
+				start, end = 0, len(line)
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Range:    lineRange(translation.Line-1, line, start, end),
+				Severity: hardcodedSeverity,
+				Code:     analyzer.RuleHardcodedString,
+				Source:   diagnosticSource,
+				Message:  fmt.Sprintf("Hardcoded string %q should be translated (confidence %.2f)", translation.Key, translation.Confidence),
+			})
+		}
+	}
+	return diagnostics
+}
+
+// lspSeverity maps a rule severity to an LSP diagnostic severity, or 0 when
+// the rule is turned off
+func lspSeverity(severity analyzer.Severity) int {
+	switch severity {
+	case analyzer.SeverityError:
+		return SeverityError
+	case analyzer.SeverityWarning:
+		return SeverityWarning
+	case analyzer.SeverityInfo:
+		return SeverityInformation
+	}
+	return 0
+}
+
+// findKey returns the byte range of a resolved key in a source line. The
+// source usually only contains the part of the key relative to the
+// namespace, so shorter suffixes are tried as well.
+func findKey(line string, key string) (int, int) {
+	for candidate := key; candidate != ""; {
+		for _, quote := range []string{"'", "\""} {
+			if i := strings.Index(line, quote+candidate+quote); i >= 0 {
+				return i + 1, i + 1 + len(candidate)
+			}
+		}
+		dot := strings.Index(candidate, ".")
+		if dot < 0 {
+			break
+		}
+		candidate = candidate[dot+1:]
+	}
+	return 0, len(line)
+}
+
+// callAt returns the resolved key of the translation call whose key literal
+// contains the given position, and the byte range of the literal.
+func (s *Server) callAt(path string, pos Position) (string, int, int, bool) {
+	text, open := s.documents[path]
+	if !open || !analyzer.IsSourceFile(path) {
+		return "", 0, 0, false
+	}
+	lines := strings.Split(text, "\n")
+	if pos.Line < 0 || pos.Line >= len(lines) {
+		return "", 0, 0, false
+	}
+	line := lines[pos.Line]
+	offset := byteOffset(line, pos.Character)
+
+	for _, match := range callPattern.FindAllStringSubmatchIndex(line, -1) {
+		keyStart, keyEnd := match[4], match[5]
+		if offset < keyStart || offset > keyEnd {
+			continue
+		}
+		scope := s.parser.ScopeAt([]byte(text), pos.Line)
+		key, ok := scope.Resolve(line[match[2]:match[3]], line[keyStart:keyEnd])
+		if !ok {
+			return "", 0, 0, false
+		}
+		return key, keyStart, keyEnd, true
+	}
+	return "", 0, 0, false
+}
+
+func (s *Server) completion(path string, pos Position) *completionList {
+	list := &completionList{Items: []completionItem{}}
+
+	text, open := s.documents[path]
+	if !open || !analyzer.IsSourceFile(path) {
+		return list
+	}
+	lines := strings.Split(text, "\n")
+	if pos.Line < 0 || pos.Line >= len(lines) {
+		return list
+	}
+	line := lines[pos.Line]
+	offset := byteOffset(line, pos.Character)
+
+	match := completionContextPattern.FindStringSubmatchIndex(line[:offset])
+	if match == nil {
+		return list
+	}
+	scope := s.parser.ScopeAt([]byte(text), pos.Line)
+	namespace, ok := scope.NamespaceOf(line[match[2]:match[3]])
+	if !ok {
+		return list
+	}
+	prefix := ""
+	if namespace != "" {
+		prefix = namespace + "."
+	}
+
+	editRange := lineRange(pos.Line, line, match[4], offset)
+	byLocale, locales := s.locales(path)
+
+	seen := make(map[string]bool)
+	for _, locale := range locales {
+		keys := make([]string, 0, len(byLocale[locale]))
+		for key := range byLocale[locale] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if !strings.HasPrefix(key, prefix) || key == prefix || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			relative := strings.TrimPrefix(key, prefix)
+			translation := byLocale[locale][key]
+			item := completionItem{
+				Label:    relative,
+				Kind:     completionKindText,
+				Detail:   translation.Value,
+				TextEdit: &textEdit{Range: editRange, NewText: relative},
+				SortText: relative,
+			}
+			if translation.Value == "" {
+				item.Kind = completionKindModule
+				item.Detail = "namespace"
+			}
+			item.Documentation = &markupContent{Kind: "markdown", Value: s.messageTable(key, byLocale, locales)}
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list
+}
+
+func (s *Server) definition(path string, pos Position) []Location {
+	locations := []Location{}
+
+	key, _, _, ok := s.callAt(path, pos)
+	if !ok {
+		return locations
+	}
+
+	files := make([]string, 0, len(s.messages))
+	for file := range s.messages {
+		if s.visibleMessages(path, file) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	segments := strings.Split(key, ".")
+	lastSegment := `"` + segments[len(segments)-1] + `"`
+
+	for _, file := range files {
+		translation, declared := s.messages[file][key]
+		if !declared || translation.Line < 1 {
+			continue
+		}
+
+		content, err := s.readFile(file)
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(string(content), "\n")
+		if translation.Line > len(lines) {
+			continue
+		}
+		line := lines[translation.Line-1]
+		start := strings.Index(line, lastSegment)
+		end := start + len(lastSegment)
+		if start < 0 {
+			start, end = 0, 0
+		}
+		locations = append(locations, Location{
+			URI:   pathToURI(file),
+			Range: lineRange(translation.Line-1, line, start, end),
+		})
+	}
+	return locations
+}
+
+func (s *Server) hover(path string, pos Position) *hover {
+	key, start, end, ok := s.callAt(path, pos)
+	if !ok {
+		return nil
+	}
+	byLocale, locales := s.locales(path)
+	lines := strings.Split(s.documents[path], "\n")
+	r := lineRange(pos.Line, lines[pos.Line], start, end)
+
+	return &hover{
+		Contents: markupContent{Kind: "markdown", Value: s.messageTable(key, byLocale, locales)},
+		Range:    &r,
+	}
+}
+
+// messageTable renders the message of key in every locale as a Markdown
+// table
+func (s *Server) messageTable(key string, byLocale map[string]map[string]analyzer.Translation, locales []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n\n", key)
+	b.WriteString("| Locale | Message |\n")
+	b.WriteString("|--------|---------|\n")
+	for _, locale := range locales {
+		value := "_missing_"
+		if translation, declared := byLocale[locale][key]; declared {
+			value = "_namespace_"
+			if translation.Value != "" {
+				value = strings.ReplaceAll(strings.ReplaceAll(translation.Value, "|", `\|`), "\n", " ")
+			}
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", locale, value)
+	}
+	return b.String()
+}
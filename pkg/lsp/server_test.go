@@ -0,0 +1,287 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"next-intl-analyzer/pkg/analyzer"
+)
+
+const testPage = `import { useTranslations } from 'next-intl';
+
+export default function Page() {
+  const t = useTranslations('Common');
+  return (
+    <div>
+      <button>{t('save')}</button>
+      <button>{t('missing')}</button>
+      <p>Welcome to our application</p>
+      {t('')}
+    </div>
+  );
+}
+`
+
+// testClient drives a server over in-memory pipes
+type testClient struct {
+	t      *testing.T
+	conn   *conn
+	lastID int
+}
+
+// startServer runs a server for the project files, written to a temporary
+// directory, until the test ends. It returns a client and the directory.
+func startServer(t *testing.T, files map[string]string) (*testClient, string) {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(serverReader, serverWriter).Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		clientWriter.Close()
+		clientReader.Close()
+		<-done
+	})
+	return &testClient{t: t, conn: newConn(clientReader, clientWriter)}, root
+}
+
+func (c *testClient) notify(method string, params interface{}) {
+	c.t.Helper()
+	if err := c.conn.write(&message{Method: method, Params: mustMarshal(params)}); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// request sends a request and decodes the result of its response into
+// result. Notifications sent by the server meanwhile are skipped.
+func (c *testClient) request(method string, params interface{}, result interface{}) {
+	c.t.Helper()
+	c.lastID++
+	id := json.RawMessage(mustMarshal(c.lastID))
+	if err := c.conn.write(&message{ID: &id, Method: method, Params: mustMarshal(params)}); err != nil {
+		c.t.Fatal(err)
+	}
+	for {
+		msg := c.read()
+		if msg.Method != "" || msg.ID == nil || string(*msg.ID) != string(id) {
+			continue
+		}
+		if msg.Error != nil {
+			c.t.Fatalf("%s: %s", method, msg.Error.Message)
+		}
+		if err := json.Unmarshal(mustMarshal(msg.Result), result); err != nil {
+			c.t.Fatal(err)
+		}
+		return
+	}
+}
+
+// next returns the next message sent by the server with the given method
+func (c *testClient) next(method string) *message {
+	c.t.Helper()
+	for {
+		if msg := c.read(); msg.Method == method {
+			return msg
+		}
+	}
+}
+
+func (c *testClient) read() *message {
+	c.t.Helper()
+	msg, err := c.conn.read()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return msg
+}
+
+// open opens a document of the project and returns the diagnostics the
+// server publishes for it
+func (c *testClient) open(path string, text string) []Diagnostic {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", didOpenParams{TextDocument: textDocumentItem{URI: pathToURI(path), Text: text}})
+	var params publishDiagnosticsParams
+	if err := json.Unmarshal(c.next("textDocument/publishDiagnostics").Params, &params); err != nil {
+		c.t.Fatal(err)
+	}
+	if params.URI != pathToURI(path) {
+		c.t.Fatalf("diagnostics published for %s, want %s", params.URI, pathToURI(path))
+	}
+	return params.Diagnostics
+}
+
+func (c *testClient) initialize(root string) {
+	c.t.Helper()
+	var result json.RawMessage
+	c.request("initialize", map[string]interface{}{
+		"rootUri": pathToURI(root),
+		"capabilities": map[string]interface{}{
+			"workspace": map[string]interface{}{
+				"didChangeWatchedFiles": map[string]bool{"dynamicRegistration": true},
+			},
+		},
+	}, &result)
+	c.notify("initialized", struct{}{})
+}
+
+func TestServer(t *testing.T) {
+	client, root := startServer(t, map[string]string{
+		"messages/en.json": `{
+  "Common": {
+    "save": "Save",
+    "cancel": "Cancel"
+  }
+}
+`,
+		"messages/de.json": `{
+  "Common": {
+    "save": "Speichern"
+  }
+}
+`,
+		"src/page.tsx": testPage,
+	})
+	client.initialize(root)
+
+	// Message files changed outside the editor are watched
+	var registration registrationParams
+	if err := json.Unmarshal(client.next("client/registerCapability").Params, &registration); err != nil {
+		t.Fatal(err)
+	}
+	if len(registration.Registrations) != 1 || registration.Registrations[0].Method != "workspace/didChangeWatchedFiles" {
+		t.Errorf("registrations = %+v, want a file watcher", registration.Registrations)
+	}
+
+	page := filepath.Join(root, "src", "page.tsx")
+	diagnostics := client.open(page, testPage)
+	var codes []string
+	for _, diagnostic := range diagnostics {
+		codes = append(codes, diagnostic.Code+" "+diagnostic.Message)
+	}
+	want := []string{
+		analyzer.RuleUndeclaredKey + ` Translation key "Common.missing" is not declared in: de, en`,
+		analyzer.RuleHardcodedString + ` Hardcoded string "Welcome to our application" should be translated (confidence 1.00)`,
+	}
+	if strings.Join(codes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diagnostics:\n%s\nwant:\n%s", strings.Join(codes, "\n"), strings.Join(want, "\n"))
+	}
+	if len(diagnostics) > 0 {
+		if r := diagnostics[0].Range; r.Start != (Position{7, 18}) || r.End != (Position{7, 25}) {
+			t.Errorf("undeclared key range = %+v, want the key literal", r)
+		}
+	}
+
+	// Keys of the translator's namespace complete inside t('')
+	var list completionList
+	client.request("textDocument/completion", textDocumentPositionParams{
+		TextDocument: textDocumentIdentifier{URI: pathToURI(page)},
+		Position:     Position{Line: 9, Character: 10},
+	}, &list)
+	var labels []string
+	for _, item := range list.Items {
+		labels = append(labels, item.Label)
+	}
+	if strings.Join(labels, " ") != "save cancel" {
+		t.Errorf("completion labels = %v, want save, then cancel", labels)
+	}
+
+	// On 'save' in t('save')
+	onKey := textDocumentPositionParams{
+		TextDocument: textDocumentIdentifier{URI: pathToURI(page)},
+		Position:     Position{Line: 6, Character: 21},
+	}
+	var locations []Location
+	client.request("textDocument/definition", onKey, &locations)
+	wantLocations := []Location{
+		{URI: pathToURI(filepath.Join(root, "messages", "de.json")), Range: Range{Start: Position{2, 4}, End: Position{2, 10}}},
+		{URI: pathToURI(filepath.Join(root, "messages", "en.json")), Range: Range{Start: Position{2, 4}, End: Position{2, 10}}},
+	}
+	if len(locations) != len(wantLocations) || locations[0] != wantLocations[0] || locations[1] != wantLocations[1] {
+		t.Errorf("definition = %+v, want %+v", locations, wantLocations)
+	}
+
+	var h hover
+	client.request("textDocument/hover", onKey, &h)
+	for _, row := range []string{"**Common.save**", "| de | Speichern |", "| en | Save |"} {
+		if !strings.Contains(h.Contents.Value, row) {
+			t.Errorf("hover does not contain %q:\n%s", row, h.Contents.Value)
+		}
+	}
+
+	var result json.RawMessage
+	client.request("shutdown", nil, &result)
+}
+
+func TestServerSkipsExcludedFiles(t *testing.T) {
+	legacy := `export function Legacy() {
+  return <p>Welcome to our application</p>;
+}
+`
+	client, root := startServer(t, map[string]string{
+		".next-intl-analyzer.json": `{"exclude": ["src/legacy"]}`,
+		".gitignore":               "generated/\n",
+		"messages/en.json":         `{"Common": {"save": "Save"}}`,
+		"src/page.tsx":             legacy,
+		"src/legacy/page.tsx":      legacy,
+		"generated/page.tsx":       legacy,
+	})
+	client.initialize(root)
+
+	if diagnostics := client.open(filepath.Join(root, "src", "page.tsx"), legacy); len(diagnostics) != 1 {
+		t.Errorf("diagnostics of an analyzed file = %+v, want one", diagnostics)
+	}
+	for _, name := range []string{"src/legacy/page.tsx", "generated/page.tsx"} {
+		if diagnostics := client.open(filepath.Join(root, filepath.FromSlash(name)), legacy); len(diagnostics) > 0 {
+			t.Errorf("%s: diagnostics = %+v, want none", name, diagnostics)
+		}
+	}
+}
+
+func TestServerWorkspaceProjects(t *testing.T) {
+	page := `import { useTranslations } from 'next-intl';
+
+export function Page() {
+  const t = useTranslations();
+  return <p>{t('Web.title')}</p>;
+}
+`
+	client, root := startServer(t, map[string]string{
+		".next-intl-analyzer.json": `{"projects": [
+  {"name": "web", "sources": ["apps/web"], "messages": ["apps/web/messages"]},
+  {"name": "admin", "sources": ["apps/admin"], "messages": ["apps/admin/messages"]}
+]}`,
+		"apps/web/messages/en.json":   `{"Web": {"title": "Welcome"}}`,
+		"apps/admin/messages/en.json": `{"Admin": {"title": "Administration"}}`,
+		"apps/web/page.tsx":           page,
+		"apps/admin/page.tsx":         page,
+	})
+	client.initialize(root)
+
+	// Keys are looked up in the messages of the project of the file
+	if diagnostics := client.open(filepath.Join(root, "apps", "web", "page.tsx"), page); len(diagnostics) > 0 {
+		t.Errorf("web: diagnostics = %+v, want none", diagnostics)
+	}
+	if diagnostics := client.open(filepath.Join(root, "apps", "admin", "page.tsx"), page); len(diagnostics) != 1 || diagnostics[0].Code != analyzer.RuleUndeclaredKey {
+		t.Errorf("admin: diagnostics = %+v, want Web.title undeclared", diagnostics)
+	}
+}
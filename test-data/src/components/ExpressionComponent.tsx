@@ -0,0 +1,45 @@
+import { useTranslations } from 'next-intl';
+import { toast } from 'sonner';
+
+// Hardcoded text outside of plain tag content. TestExpressionComponent in
+// pkg/analyzer/hardcoded_test.go asserts which strings are reported as
+// hardcoded-string.
+export const metadata = {
+    title: 'Account settings',
+    description: 'Manage your profile and notification preferences',
+};
+
+const billingOptions = [
+    { label: 'Monthly', value: 'monthly' },
+    { label: 'Yearly', value: 'yearly' },
+];
+
+function ExpressionComponent({ name }: { name: string }) {
+    const t = useTranslations('Common');
+
+    const save = () => {
+        toast.error('Something went wrong');
+        document.title = 'Settings';
+        if (!window.confirm('Discard unsaved changes?')) {
+            return;
+        }
+    };
+
+    return (
+        <div>
+            <p>{'Save changes'}</p>
+            <p>{`Hello ${name}`}</p>
+            <TextField label="Email" helperText={'We never share your email'} />
+            <Select options={billingOptions} />
+
+            {/* Not user-facing */}
+            <button onClick={save}>{t('button.save')}</button>
+            <TextField label={t('button.cancel')} type="email" variant="outlined" />
+            <Icon title="arrowLeft" name="arrow-left" />
+            <Link href="/settings" className="nav-link">{' '}</Link>
+            <Image alt="" src="/logo.png" />
+        </div>
+    );
+}
+
+export default ExpressionComponent;
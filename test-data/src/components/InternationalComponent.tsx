@@ -0,0 +1,43 @@
+import { useTranslations } from 'next-intl';
+
+// Hardcoded text in non-Latin scripts. TestInternationalComponent in
+// pkg/analyzer/script_test.go asserts which strings are reported as
+// hardcoded-string.
+function InternationalComponent() {
+    const t = useTranslations('Common');
+
+    return (
+        <div>
+            {/* Japanese */}
+            <h1>ようこそ</h1>
+            <button>保存</button>
+            <p>この操作は元に戻せません。</p>
+            <input placeholder="メールアドレスを入力" />
+
+            {/* Chinese */}
+            <h2>欢迎使用我们的应用</h2>
+            <span>加载中…</span>
+
+            {/* Korean */}
+            <button>저장하기</button>
+
+            {/* Cyrillic */}
+            <h2>Добро пожаловать</h2>
+            <button>Сохранить</button>
+            <p>Ваши изменения были сохранены.</p>
+
+            {/* Arabic and Hebrew (right-to-left) */}
+            <h2>مرحبا بكم في تطبيقنا</h2>
+            <button>حفظ التغييرات</button>
+            <p>ברוכים הבאים לאפליקציה שלנו</p>
+
+            {/* Not user-facing */}
+            <button>{t('button.save')}</button>
+            <span>2024年</span>
+            <span>123</span>
+            <span>userName</span>
+        </div>
+    );
+}
+
+export default InternationalComponent;
@@ -0,0 +1,13 @@
+import { useTranslations } from 'next-intl';
+
+export default function AdminPage() {
+  const t = useTranslations('Common');
+
+  return (
+    <div>
+      <button>{t('save')}</button>
+      <button>{t('missing')}</button>
+      <p>Only administrators can see this page</p>
+    </div>
+  );
+}
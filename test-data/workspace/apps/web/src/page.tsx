@@ -0,0 +1,14 @@
+import { useTranslations } from 'next-intl';
+
+export default function HomePage() {
+  const t = useTranslations();
+
+  return (
+    <div>
+      <h1>{t('Web.title')}</h1>
+      <a href="/admin">{t('Admin.users')}</a>
+      <button>{t('Common.save')}</button>
+      <button>{t('Common.cancel')}</button>
+    </div>
+  );
+}
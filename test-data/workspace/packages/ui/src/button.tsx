@@ -0,0 +1,7 @@
+import { useTranslations } from 'next-intl';
+
+export function DeleteButton() {
+  const t = useTranslations('Common');
+
+  return <button>{t('delete')}</button>;
+}